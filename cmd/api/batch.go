@@ -0,0 +1,235 @@
+package main
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"tools.lucasfaria.dev/internal/annotate"
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+const (
+	maxBatchSize = 500
+	// every PDF takes a round trip to Gotenberg, so a batch gets far more
+	// time than the server-wide write timeout allows
+	batchWriteTimeout = 10 * time.Minute
+)
+
+type batchManifestEntry struct {
+	File        string                `json:"file"`
+	Seed        int64                 `json:"seed"`
+	Invoice     generate.InvoiceData  `json:"invoice"`
+	Annotations *annotate.Annotations `json:"annotations,omitempty"`
+	TaxFormFile string                `json:"taxFormFile,omitempty"`
+	TaxForm     any                   `json:"taxForm,omitempty"`
+}
+
+type batchResult struct {
+	pdf         []byte
+	annotations *annotate.Annotations
+	taxForm     []byte
+	taxFormData any
+	err         error
+}
+
+func (app *application) createFakeInvoiceBatch(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+	options, renderOptions := app.readFakeInvoiceOptions(qs, v)
+	count := app.readInt(qs, "count", 10, v)
+	taxForm := strings.ToLower(app.readString(qs, "taxForm", ""))
+
+	v.Check(count >= 1 && count <= maxBatchSize, "count", fmt.Sprintf("must be between 1 and %d", maxBatchSize))
+	if taxForm != "" {
+		v.Check(validator.PermittedValue(taxForm, generate.TaxForms...), "taxForm", fmt.Sprintf("must be one of %v", generate.TaxForms))
+		checkTaxFormVendor(v, taxForm, options)
+	}
+
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	app.logger.Info("Creating invoice batch", "count", count, "seed", options.Seed, "taxForm", taxForm)
+
+	// each document gets its own seed, derived from the batch seed, so any
+	// single invoice of the batch can be reproduced with GET /v1/invoices/fake
+	invoices := make([]generate.InvoiceData, count)
+	for i := range invoices {
+		var err error
+		invoices[i], err = generate.GenerateRandomInvoiceData(documentOptions(options, i))
+		if err != nil {
+			app.fakeDocumentErrorResponse(w, r, v, taxRegionKey(options), err)
+			return
+		}
+	}
+
+	// render with bounded concurrency; every result has its own channel so
+	// the archive is written in order while later documents are rendering.
+	// A slot is only freed once the writer has taken the result, so no more
+	// than concurrency rendered documents are ever held in memory
+	results := make([]chan batchResult, count)
+	for i := range results {
+		results[i] = make(chan batchResult, 1)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	semaphore := make(chan struct{}, max(1, app.config.batch.concurrency))
+	go func() {
+		for i := range invoices {
+			select {
+			case semaphore <- struct{}{}:
+			case <-done:
+				return
+			}
+
+			go func(i int) {
+				results[i] <- app.renderBatchDocument(&invoices[i], taxForm, documentOptions(options, i), renderOptions)
+			}(i)
+		}
+	}()
+
+	// surface a failure of the first document as a regular error response;
+	// after that the headers are gone and the archive is simply cut short
+	first := <-results[0]
+	<-semaphore
+	if first.err != nil {
+		app.serverErrorResponse(w, r, first.err)
+		return
+	}
+
+	err := http.NewResponseController(w).SetWriteDeadline(time.Now().Add(batchWriteTimeout))
+	if err != nil {
+		app.logError(r, fmt.Errorf("failed to extend write deadline: %v", err))
+	}
+
+	w.Header().Set("X-Seed", strconv.FormatInt(options.Seed, 10))
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", `attachment; filename="invoices.zip"`)
+
+	zw := zip.NewWriter(w)
+	manifest := make([]batchManifestEntry, 0, count)
+
+	for i := range invoices {
+		result := first
+		if i > 0 {
+			result = <-results[i]
+			<-semaphore
+		}
+		if result.err != nil {
+			app.logError(r, fmt.Errorf("batch aborted at document %d: %v", i+1, result.err))
+			return
+		}
+
+		name := fmt.Sprintf("invoice-%04d.pdf", i+1)
+		if err := writeZipFile(zw, name, result.pdf); err != nil {
+			app.logError(r, err)
+			return
+		}
+
+		entry := batchManifestEntry{
+			File:        name,
+			Seed:        options.Seed + int64(i),
+			Invoice:     invoices[i],
+			Annotations: result.annotations,
+		}
+
+		if result.taxForm != nil {
+			entry.TaxFormFile = fmt.Sprintf("invoice-%04d-%s.pdf", i+1, taxForm)
+			entry.TaxForm = result.taxFormData
+			if err := writeZipFile(zw, entry.TaxFormFile, result.taxForm); err != nil {
+				app.logError(r, err)
+				return
+			}
+		}
+
+		manifest = append(manifest, entry)
+	}
+
+	if err := writeManifest(zw, manifest); err != nil {
+		app.logError(r, err)
+		return
+	}
+
+	if err := zw.Close(); err != nil {
+		app.logError(r, err)
+		return
+	}
+
+	app.logger.Info("Successfully sent invoice batch to client", "count", count)
+}
+
+// documentOptions are the options of the i-th document of a batch, whose
+// seed is derived from the batch seed.
+func documentOptions(options *generate.GenerateInvoiceOptions, i int) *generate.GenerateInvoiceOptions {
+	document := *options
+	document.Seed = options.Seed + int64(i)
+	return &document
+}
+
+// renderBatchDocument renders an invoice of the batch, and when one is asked
+// for the tax form of its vendor, drawn from the same invoice options.
+func (app *application) renderBatchDocument(data *generate.InvoiceData, taxForm string, invoiceOptions *generate.GenerateInvoiceOptions, options *generate.RenderOptions) batchResult {
+	pdf, err := app.render("pdf", generate.InvoiceTemplate, data, options)
+	if err != nil {
+		return batchResult{err: err}
+	}
+	result := batchResult{pdf: pdf}
+
+	if taxForm != "" {
+		result.taxFormData = fakeTaxFormData(taxForm, invoiceOptions)
+		result.taxForm, err = app.render("pdf", taxFormTemplates[taxForm], result.taxFormData, taxFormRenderOptions)
+		if err != nil {
+			return batchResult{err: err}
+		}
+	}
+
+	if options.Annotate {
+		result.annotations, err = annotate.Extract(pdf, annotate.Letter)
+		if err != nil {
+			return batchResult{err: fmt.Errorf("failed to extract annotations: %v", err)}
+		}
+	}
+
+	return result
+}
+
+func writeZipFile(zw *zip.Writer, name string, content []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to add %s to archive: %v", name, err)
+	}
+
+	if _, err := f.Write(content); err != nil {
+		return fmt.Errorf("failed to write %s to archive: %v", name, err)
+	}
+
+	return nil
+}
+
+// writeManifest adds manifest.jsonl to the archive, one JSON document per
+// line in the same order as the files.
+func writeManifest(zw *zip.Writer, entries []batchManifestEntry) error {
+	f, err := zw.Create("manifest.jsonl")
+	if err != nil {
+		return fmt.Errorf("failed to add manifest to archive: %v", err)
+	}
+
+	enc := json.NewEncoder(f)
+	for _, entry := range entries {
+		if err := enc.Encode(entry); err != nil {
+			return fmt.Errorf("failed to write manifest: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestCreateFakeInvoiceBatchValidation(t *testing.T) {
+	app := &application{}
+
+	testInvalidOptions(t, app.createFakeInvoiceBatch, http.MethodPost, "/v1/invoices/fake/batch", []invalidOptionsTest{
+		{"Count too large", "?count=501", "count"},
+		{"Count too small", "?count=0", "count"},
+		{"Count not a number", "?count=many", "count"},
+		{"Invalid shared option", "?count=5&numberOfItems=50", "numberOfItems"},
+		{"Unknown tax form", "?count=5&taxForm=1099", "taxForm"},
+		{"Tax form of another country", "?count=5&taxForm=w8ben", "vendorCountry"},
+		{"Company on a W-8BEN", "?count=5&taxForm=w8ben&vendorCountry=DE", "vendorName"},
+	})
+}
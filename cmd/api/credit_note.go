@@ -0,0 +1,119 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+// pairedCreditNoteArchive renders a credit note and the invoice it corrects
+// to PDF and bundles them.
+func (app *application) pairedCreditNoteArchive(creditNote *generate.CreditNoteData, invoice *generate.InvoiceData, options *generate.RenderOptions) ([]byte, error) {
+	invoicePdf, err := app.render("pdf", generate.InvoiceTemplate, invoice, options)
+	if err != nil {
+		return nil, err
+	}
+	creditNotePdf, err := app.render("pdf", generate.CreditNoteTemplate, creditNote, options)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := writeZipFile(zw, "invoice.pdf", invoicePdf); err != nil {
+		return nil, err
+	}
+	if err := writeZipFile(zw, "credit-note.pdf", creditNotePdf); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// createFakeCreditNote generates the invoice of the fake invoice options and a
+// credit note correcting it. JSON responses always carry both; PDF responses
+// only the credit note, unless paired=true asks for an archive of the two.
+func (app *application) createFakeCreditNote(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+	options, renderOptions := app.readFakeInvoiceOptions(qs, v)
+	reason := strings.ToLower(app.readString(qs, "reason", ""))
+	paired := app.readBool(qs, "paired", false, v)
+	format := app.readFormat(r, qs, v, "pdf", "json", "html")
+	if reason != "" {
+		v.Check(validator.PermittedValue(reason, generate.CreditReasonNames...), "reason", fmt.Sprintf("must be one of %v", generate.CreditReasonNames))
+	}
+	v.Check(!paired || format == "pdf", "paired", "is only available for PDF output")
+	v.Check(!renderOptions.Annotate, "annotations", "are only available for invoices")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	app.logger.Info("Creating credit note with the following parameters: " +
+		fmt.Sprintf("seed=%v, vendorName=%v, numberOfItems=%v, invoiceDate=%v, currency=%v, reason=%v, paired=%v, format=%v",
+			options.Seed, options.VendorName, options.NumberOfItems, options.InvoiceDate, options.Currency, reason, paired, format))
+
+	creditNote, invoice, err := generate.GenerateRandomCreditNoteData(options, reason)
+	if err != nil {
+		app.fakeDocumentErrorResponse(w, r, v, taxRegionKey(options), err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("X-Seed", strconv.FormatInt(options.Seed, 10))
+
+	switch format {
+	case "json":
+		err := app.writeJSON(w, http.StatusOK, envelope{"creditNote": creditNote, "invoice": invoice}, headers)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+
+	}
+
+	if paired {
+		archive, err := app.pairedCreditNoteArchive(&creditNote, &invoice, renderOptions)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		headers.Set("Content-Disposition", `attachment; filename="credit-note.zip"`)
+		app.writeDocument(w, r, "application/zip", archive, headers)
+		return
+	}
+
+	content, err := app.render(format, generate.CreditNoteTemplate, &creditNote, renderOptions)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.logger.Info("Sending " + format + " content to client...")
+	app.writeDocument(w, r, contentTypes[format], content, headers)
+}
+
+func (app *application) createCreditNote(w http.ResponseWriter, r *http.Request) {
+	createDocument(app, w, r, postedDocument[generate.CreditNoteData]{
+		name:           "credit note",
+		template:       generate.CreditNoteTemplate,
+		validate:       generate.ValidateCreditNote,
+		validateTotals: generate.ValidateCreditNoteTotals,
+		items:          func(d *generate.CreditNoteData) *[]generate.InvoiceItem { return &d.Items },
+		logo:           func(d *generate.CreditNoteData) *string { return &d.CompanyLogo },
+		regionKey:      func(d *generate.CreditNoteData) string { return postedRegionKey(d.VendorInfo) },
+	})
+}
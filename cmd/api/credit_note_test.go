@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/generate"
+)
+
+func TestCreateFakeCreditNoteJSON(t *testing.T) {
+	app := newTestApplication()
+
+	rr := serve(app.createFakeCreditNote, http.MethodGet, "/v1/credit-notes/fake?seed=7&reason=duplicate&format=json", "")
+
+	assert.Equal(t, rr.Code, http.StatusOK)
+	assert.Equal(t, rr.Header().Get("X-Seed"), "7")
+
+	var response struct {
+		CreditNote generate.CreditNoteData `json:"creditNote"`
+		Invoice    generate.InvoiceData    `json:"invoice"`
+	}
+	decodeResponse(t, rr, &response)
+
+	assert.Equal(t, response.CreditNote.OriginalInvoiceNumber, response.Invoice.InvoiceNumber)
+	assert.Equal(t, response.CreditNote.Reason, "Duplicate billing")
+	assert.Equal(t, response.CreditNote.Total, -response.Invoice.Total)
+}
+
+func TestCreateFakeCreditNoteInvalidOptions(t *testing.T) {
+	app := newTestApplication()
+
+	testInvalidOptions(t, app.createFakeCreditNote, http.MethodGet, "/v1/credit-notes/fake", []invalidOptionsTest{
+		{"Unknown reason", "?reason=goodwill", "reason"},
+		{"Paired JSON", "?paired=true&format=json", "paired"},
+	})
+}
+
+func TestCreateCreditNoteValidation(t *testing.T) {
+	app := newTestApplication()
+
+	testValidation(t, app.createCreditNote, "/v1/credit-notes", []validationTest{
+		{"Empty credit note", `{}`, []string{"CreditNoteNumber", "OriginalInvoiceNumber", "Reason", "Items"}},
+		{"Credit before invoice", `{
+			"CreditNoteNumber": "CN-1", "CreditNoteDate": "2024-03-01", "OriginalInvoiceNumber": "1", "OriginalInvoiceDate": "2024-03-05",
+			"Reason": "Returned goods", "Currency": "USD", "VendorInfo": {"Name": "Globex"}, "CustomerInfo": {"Name": "Acme Corp."},
+			"Items": [{"Description": "Widgets", "Quantity": 2, "UnitPrice": 500}]
+		}`, []string{"CreditNoteDate"}},
+		{"Positive total", `{
+			"CreditNoteNumber": "CN-1", "CreditNoteDate": "2024-03-10", "OriginalInvoiceNumber": "1", "OriginalInvoiceDate": "2024-03-05",
+			"Reason": "Returned goods", "Currency": "USD", "VendorInfo": {"Name": "Globex"}, "CustomerInfo": {"Name": "Acme Corp."},
+			"Items": [{"Description": "Widgets", "Quantity": 2, "UnitPrice": 500}],
+			"Total": 1000
+		}`, []string{"Total"}},
+	})
+}
@@ -0,0 +1,14 @@
+package main
+
+import (
+	"net/http"
+
+	"tools.lucasfaria.dev/internal/money"
+)
+
+func (app *application) listCurrenciesHandler(w http.ResponseWriter, r *http.Request) {
+	err := app.writeJSON(w, http.StatusOK, envelope{"currencies": money.Currencies()}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
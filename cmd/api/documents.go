@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/convert"
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+// contentTypes are the media types of the formats documents render to.
+var contentTypes = map[string]string{
+	"html": "text/html; charset=utf-8",
+	"pdf":  "application/pdf",
+	"png":  "image/png",
+}
+
+// render fills the document template with data and returns it as format:
+// the HTML itself, a PNG screenshot or, for any other format, a PDF. The
+// request options adjust the Gotenberg conversion, such as the receipt paper.
+func (app *application) render(format, template string, data any, options *generate.RenderOptions, requestOptions ...convert.RequestOption) ([]byte, error) {
+	file, err := generate.GenerateHtml(template, data, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s file: %v", template, err)
+	}
+	defer os.Remove(file.Name())
+
+	switch format {
+	case "html":
+		return os.ReadFile(file.Name())
+
+	case "png":
+		content, err := convert.HtmlToPng(file, requestOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert HTML to image: %v", err)
+		}
+		return content, nil
+	}
+
+	content, err := convert.HtmlToPdfV2(file, requestOptions...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert HTML to PDF: %v", err)
+	}
+	return content, nil
+}
+
+// itemized is the data of a document priced by its line items.
+type itemized[T any] interface {
+	*T
+	CalculateTotals()
+	ApplyTax(engine *tax.Engine) error
+}
+
+// postedDocument describes a kind of document createDocument renders from
+// the data a client posts.
+type postedDocument[T any] struct {
+	name     string
+	template string
+	page     []convert.RequestOption
+
+	// decode reads the request into data, with readJSON when nil
+	decode         func(w http.ResponseWriter, r *http.Request, v *validator.Validator, data *T) error
+	validate       func(v *validator.Validator, data *T)
+	validateTotals func(v *validator.Validator, sent, calculated *T)
+
+	// items points at the line items, whose amounts CalculateTotals derives
+	items func(data *T) *[]generate.InvoiceItem
+	// logo points at the logo URL of the documents that carry one
+	logo func(data *T) *string
+
+	// regionKey names the field a tax.ErrUnknownRegion is reported under
+	regionKey func(data *T) string
+}
+
+// createDocument renders the document a client posts to PDF: the data is
+// validated, its totals derived from the line items and taxed by the tax
+// engine, and the amounts the client sent checked against them.
+func createDocument[T any, P itemized[T]](app *application, w http.ResponseWriter, r *http.Request, doc postedDocument[T]) {
+	var input T
+	app.logger.Info("Creating " + doc.name + " with the JSON body")
+
+	v := validator.New()
+	qs := r.URL.Query()
+	lang := app.readLanguage(qs, "language", language.AmericanEnglish, v)
+	locale := app.readLocale(qs, "locale", lang, v)
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	var err error
+	if doc.decode != nil {
+		err = doc.decode(w, r, v, &input)
+	} else {
+		err = app.readJSON(w, r, &input)
+	}
+	if err != nil {
+		app.logger.Error("failed to decode "+doc.name+" data", "error", err.Error())
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if doc.validate(v, &input); !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	// totals are always derived from the line items; the ones the client
+	// sent are only checked against them
+	sent := input
+	*doc.items(&sent) = slices.Clone(*doc.items(&input))
+	P(&input).CalculateTotals()
+
+	err = P(&input).ApplyTax(app.taxEngine)
+	switch {
+	case errors.Is(err, tax.ErrUnsupportedJurisdiction):
+		// no rules for the vendor country, keep the per-line tax rates
+	case errors.Is(err, tax.ErrUnknownRegion):
+		v.AddErrorCode(doc.regionKey(&input), validator.CodeNotAllowed, err.Error())
+		app.failedValidationResponse(w, r, v)
+		return
+	case err != nil:
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if doc.validateTotals(v, &sent, &input); !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	if doc.logo != nil {
+		// the logo is the only client URL in the templates, and Gotenberg
+		// would fetch it from inside the network
+		logo := doc.logo(&input)
+		*logo, err = app.config.resources.Image(r.Context(), *logo)
+		if err != nil {
+			v.AddErrorCode("CompanyLogo", validator.CodeNotAllowed, err.Error())
+			app.failedValidationResponse(w, r, v)
+			return
+		}
+	}
+
+	app.logger.Info("Rendering " + doc.name + " to PDF")
+	pdfContent, err := app.render("pdf", doc.template, &input, &generate.RenderOptions{Language: lang, Locale: locale}, doc.page...)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.logger.Info("Sending PDF content to client")
+	app.writeDocument(w, r, "application/pdf", pdfContent, nil)
+}
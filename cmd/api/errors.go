@@ -3,6 +3,8 @@ package main
 import (
 	"fmt"
 	"net/http"
+
+	"tools.lucasfaria.dev/internal/validator"
 )
 
 func (app *application) logError(r *http.Request, err error) {
@@ -43,8 +45,19 @@ func (app *application) methodNotAllowedResponse(w http.ResponseWriter, r *http.
 	app.errorResponse(w, r, http.StatusMethodNotAllowed, message)
 }
 
-func (app *application) failedValidationResponse(w http.ResponseWriter, r *http.Request, errors map[string]string) {
-	app.errorResponse(w, r, http.StatusUnprocessableEntity, errors)
+// failedValidationResponse keeps "error" as one message per field for
+// existing clients, and lists every error with its code under "fields".
+func (app *application) failedValidationResponse(w http.ResponseWriter, r *http.Request, v *validator.Validator) {
+	env := envelope{
+		"error":  v.Messages(),
+		"fields": v.Errors,
+	}
+
+	err := app.writeJSON(w, http.StatusUnprocessableEntity, env, nil)
+	if err != nil {
+		app.logError(r, err)
+		w.WriteHeader(500)
+	}
 }
 
 func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http.Request) {
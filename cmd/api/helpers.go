@@ -1,17 +1,20 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
-	"math/rand"
 	"net/http"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/i18n"
 	"tools.lucasfaria.dev/internal/validator"
 )
 
@@ -21,7 +24,13 @@ func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst any
 	maxBytes := 1_048_576 // limit request body to 1MB
 	r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
 
-	dec := json.NewDecoder(r.Body)
+	return app.decodeJSON(r.Body, dst)
+}
+
+// decodeJSON decodes a single JSON value from body into dst, turning decoding
+// errors into messages fit for the client.
+func (app *application) decodeJSON(body io.Reader, dst any) error {
+	dec := json.NewDecoder(body)
 	dec.DisallowUnknownFields()
 
 	err := dec.Decode(dst)
@@ -93,6 +102,36 @@ func (app *application) writeJSON(w http.ResponseWriter, status int, data envelo
 	return nil
 }
 
+// writeDocument sends a rendered document, such as a PDF, as the response body.
+func (app *application) writeDocument(w http.ResponseWriter, r *http.Request, contentType string, content []byte, headers http.Header) {
+	for key, value := range headers {
+		w.Header()[key] = value
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+	if _, err := io.Copy(w, bytes.NewReader(content)); err != nil {
+		app.logError(r, fmt.Errorf("failed to send document to client: %v", err))
+	}
+}
+
+// readFormat picks the response format from the format query parameter, then
+// from the Accept header (only application/json is negotiated that way, so
+// browsers keep getting the default), then falls back to the first permitted
+// format.
+func (app *application) readFormat(r *http.Request, qs url.Values, v *validator.Validator, permitted ...string) string {
+	if format := strings.ToLower(qs.Get("format")); format != "" {
+		v.Check(validator.PermittedValue(format, permitted...), "format", fmt.Sprintf("must be one of %v", permitted))
+		return format
+	}
+
+	if slices.Contains(permitted, "json") && strings.Contains(r.Header.Get("Accept"), "application/json") {
+		return "json"
+	}
+
+	return permitted[0]
+}
+
 func (app *application) readString(qs url.Values, key string, defaultValue string) string {
 	s := qs.Get(key)
 
@@ -119,6 +158,35 @@ func (app *application) readDate(qs url.Values, key string, defaultValue time.Ti
 	return t
 }
 
+func (app *application) readLocale(qs url.Values, key string, defaultValue language.Tag, v *validator.Validator) language.Tag {
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue
+	}
+
+	tag, err := language.Parse(s)
+	if err != nil {
+		v.AddError(key, "must be a valid BCP 47 language tag, such as en-US or pt-BR")
+		return defaultValue
+	}
+
+	return tag
+}
+
+// readLanguage reads a language tag and checks there is a message catalog
+// for it, so documents are never silently rendered in English.
+func (app *application) readLanguage(qs url.Values, key string, defaultValue language.Tag, v *validator.Validator) language.Tag {
+	tag := app.readLocale(qs, key, defaultValue, v)
+
+	if _, ok := i18n.Match(tag); !ok {
+		v.AddError(key, fmt.Sprintf("must be one of the supported languages %v", i18n.Supported()))
+		return defaultValue
+	}
+
+	return tag
+}
+
 func (app *application) readCSV(qs url.Values, key string, defaultValue []string) []string {
 	s := qs.Get(key)
 
@@ -129,6 +197,22 @@ func (app *application) readCSV(qs url.Values, key string, defaultValue []string
 	return strings.Split(s, ",")
 }
 
+func (app *application) readBool(qs url.Values, key string, defaultValue bool, v *validator.Validator) bool {
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		v.AddError(key, "must be a boolean")
+		return defaultValue
+	}
+
+	return b
+}
+
 func (app *application) readInt(qs url.Values, key string, defaultValue int, v *validator.Validator) int {
 	s := qs.Get(key)
 
@@ -145,6 +229,27 @@ func (app *application) readInt(qs url.Values, key string, defaultValue int, v *
 	return i
 }
 
+// readCount reads a number of things to generate. A missing key returns zero,
+// which the generators replace with a count drawn from the seed, so an
+// explicit zero is reported rather than read the same way.
+func (app *application) readCount(qs url.Values, key string, v *validator.Validator) int {
+	s := qs.Get(key)
+
+	if s == "" {
+		return 0
+	}
+
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		v.AddError(key, "must be an integer")
+		return 0
+	}
+
+	v.CheckCode(i != 0, key, validator.CodeOutOfRange, "must not be less than 1")
+
+	return i
+}
+
 func (app *application) readInt64(qs url.Values, key string, defaultValue int64, v *validator.Validator) int64 {
 	s := qs.Get(key)
 
@@ -160,9 +265,3 @@ func (app *application) readInt64(qs url.Values, key string, defaultValue int64,
 
 	return i
 }
-
-func (app *application) getRandomAccountNumber() int64 {
-	min := int64(1e8)  // The smallest 9 digit number
-	max := int64(1e12) // The smallest 13 digit number
-	return min + rand.Int63n(max-min)
-}
@@ -1,139 +1,352 @@
 package main
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"image/png"
 	"io"
 	"math/rand"
+	"mime"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
-	"tools.lucasfaria.dev/internal/convert"
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/annotate"
 	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/imaging"
+	"tools.lucasfaria.dev/internal/tax"
 	"tools.lucasfaria.dev/internal/validator"
 )
 
-var validCurrencies = []string{
-	"usd", // United States Dollar
-	"eur", // Euro
-	"jpy", // Japanese Yen
-	"gbp", // British Pound Sterling
-	"aud", // Australian Dollar
-	"cad", // Canadian Dollar
-	"chf", // Swiss Franc
-	"cny", // Chinese Yuan
-	"sek", // Swedish Krona
-	"nzd", // New Zealand Dollar
-	"mxn", // Mexican Peso
-	"sgd", // Singapore Dollar
-	"hkd", // Hong Kong Dollar
-	"nok", // Norwegian Krone
-	"krw", // South Korean Won
-	"try", // Turkish Lira
-	"rub", // Russian Ruble
-	"inr", // Indian Rupee
-	"brl", // Brazilian Real
-	"zar", // South African Rand
-}
-
-func (app *application) createFakeInvoice(w http.ResponseWriter, r *http.Request) {
-	v := validator.New()
-
+// readFakeInvoiceOptions reads the query string shared by the fake invoice
+// endpoints. Anything that is not given is left for the generator to draw
+// from the seed, so a seed alone reproduces the whole invoice.
+func (app *application) readFakeInvoiceOptions(qs url.Values, v *validator.Validator) (*generate.GenerateInvoiceOptions, *generate.RenderOptions) {
 	now := time.Now()
-	qs := r.URL.Query()
-	paymentMethods := app.readCSV(qs, "paymentMethods", []string{"ach"})
+	seed := app.readInt64(qs, "seed", now.UnixNano(), v)
+	paymentMethods := app.readCSV(qs, "paymentMethods", nil)
 	for i, method := range paymentMethods {
 		paymentMethods[i] = strings.ToLower(method)
 	}
 	vendorName := app.readString(qs, "vendorName", "")
-	accountNumber := app.readInt64(qs, "accountNumber", app.getRandomAccountNumber(), v)
-	numberOfItems := app.readInt(qs, "numberOfItems", rand.Intn(8)+1, v)
+	accountNumber := app.readInt64(qs, "accountNumber", 0, v)
+	numberOfItems := app.readCount(qs, "numberOfItems", v)
 	invoiceDate := app.readDate(qs, "createdAt", now, v)
 	dueDate := app.readDate(qs, "dueAt", now.AddDate(0, 0, 30), v)
 	currency := strings.ToLower(app.readString(qs, "currency", "usd"))
-
-	v.Check(validator.PermittedValues(paymentMethods, []string{"ach", "check", "wire"}), "paymentMethods", "must be list of ['ach', 'check', 'wire']")
-	v.Check(numberOfItems >= 1 && numberOfItems <= 20, "numberOfItems", "must be between 1 and 20")
-	v.Check(invoiceDate.Before(dueDate), "invoiceDate", "must be before dueDate")
-	v.Check(validator.PermittedValue(currency, validCurrencies...), "currency", fmt.Sprintf("must be one of %v", validCurrencies))
-
-	if !v.Valid() {
-		app.failedValidationResponse(w, r, v.Errors)
-		return
+	lang := app.readLanguage(qs, "language", language.AmericanEnglish, v)
+	locale := app.readLocale(qs, "locale", lang, v)
+	vendorCountry := app.readString(qs, "vendorCountry", "US")
+	vendorRegion := app.readString(qs, "vendorRegion", "")
+	customer := tax.Jurisdiction{
+		Country: app.readString(qs, "customerCountry", "US"),
+		Region:  app.readString(qs, "customerRegion", ""),
+		TaxID:   app.readString(qs, "customerTaxId", ""),
 	}
 
-	app.logger.Info("Creating invoice with the following parameters: " +
-		fmt.Sprintf("paymentMethods=%v, vendorName=%v, accountNumber=%v, numberOfItems=%v, invoiceDate=%v, dueDate=%v, currency=%v",
-			paymentMethods, vendorName, accountNumber, numberOfItems, invoiceDate, dueDate, currency))
-
-	randomInvoice := generate.GenerateRandomInvoiceData(&generate.GenerateInvoiceOptions{
+	options := &generate.GenerateInvoiceOptions{
+		Seed:           seed,
 		PaymentMethods: paymentMethods,
 		VendorName:     vendorName,
 		AccountNumber:  accountNumber,
 		NumberOfItems:  numberOfItems,
-		InvoiceDate:    invoiceDate.Format("January 2, 2006"),
-		DueDate:        dueDate.Format("January 2, 2006"),
+		InvoiceDate:    invoiceDate.Format(time.DateOnly),
+		DueDate:        dueDate.Format(time.DateOnly),
 		Currency:       currency,
-	})
+		VendorCountry:  vendorCountry,
+		VendorRegion:   vendorRegion,
+		Customer:       customer,
+		TaxEngine:      app.taxEngine,
 
-	invoiceHtml, err := generate.GenerateInvoiceHtml(&randomInvoice)
+		RandomCustomer:  app.readBool(qs, "randomCustomer", false, v),
+		CustomerName:    app.readString(qs, "customerName", ""),
+		CustomerAddress: app.readString(qs, "customerAddress", ""),
+		CustomerCity:    app.readString(qs, "customerCity", ""),
+		CustomerEmail:   app.readString(qs, "customerEmail", ""),
+		ShipTo:          app.readBool(qs, "shipTo", false, v),
+		RemitTo:         app.readBool(qs, "remitTo", false, v),
+	}
 
-	if err != nil {
-		app.serverErrorResponse(w, r, fmt.Errorf("failed to create index.html file: %v", err))
-		return
+	v.Struct(options)
+	// the customer jurisdiction has no tags, it is only read from here
+	v.CheckCode(len(customer.Country) == 2, "customerCountry", validator.CodeOutOfRange, "must have exactly 2 characters")
+	v.CheckCode(validator.MaxChars(customer.Region, 3), "customerRegion", validator.CodeOutOfRange, "must not have more than 3 characters")
+	v.CheckCode(validator.MaxChars(customer.TaxID, 32), "customerTaxId", validator.CodeOutOfRange, "must not have more than 32 characters")
+	v.Check(validator.PermittedValues(paymentMethods, generate.PaymentRails), "paymentMethods", fmt.Sprintf("must be list of %v", generate.PaymentRails))
+	v.Check(invoiceDate.Before(dueDate), "invoiceDate", "must be before dueDate")
+
+	renderOptions := &generate.RenderOptions{
+		Language: lang,
+		Locale:   locale,
+		Annotate: app.readBool(qs, "annotations", false, v),
 	}
 
-	app.logger.Info("Converting HTML to PDF v2...")
-	pdfContent, err := convert.HtmlToPdfV2(invoiceHtml)
-	if err != nil {
-		app.serverErrorResponse(w, r, fmt.Errorf("failed to convert HTML to PDF: %v", err))
-		return
+	return options, renderOptions
+}
+
+// taxRegionKey names the query parameter a tax.ErrUnknownRegion drawing the
+// documents of options is about, see unknownRegionKey.
+func taxRegionKey(options *generate.GenerateInvoiceOptions) string {
+	return unknownRegionKey(tax.Jurisdiction{Country: options.VendorCountry, Region: options.VendorRegion}, "vendorRegion", "customerRegion")
+}
+
+// postedRegionKey names the field a tax.ErrUnknownRegion taxing a posted
+// document sold by vendor is about, see unknownRegionKey.
+func postedRegionKey(vendor generate.CompanyInfo) string {
+	return unknownRegionKey(vendor.Jurisdiction(), "VendorInfo.Region", "CustomerInfo.Region")
+}
+
+// unknownRegionKey picks the key of the party a tax.ErrUnknownRegion is
+// about: the vendor when it is an Indian one without a state, which GST
+// can't do without, the customer otherwise.
+func unknownRegionKey(vendor tax.Jurisdiction, vendorKey, customerKey string) string {
+	if strings.EqualFold(strings.TrimSpace(vendor.Country), "IN") && strings.TrimSpace(vendor.Region) == "" {
+		return vendorKey
 	}
+	return customerKey
+}
 
-	app.logger.Info("Sending PDF content to client...")
-	w.Header().Set("Content-Type", "application/pdf")
-	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pdfContent)))
-	if _, err := io.Copy(w, bytes.NewReader(pdfContent)); err != nil {
-		app.serverErrorResponse(w, r, fmt.Errorf("failed to send PDF content to client: %v", err))
+// fakeDocumentErrorResponse responds to a generator error: a region the tax
+// engine doesn't know fails the validation of key, like the region of a
+// posted invoice does, anything else is a server error.
+func (app *application) fakeDocumentErrorResponse(w http.ResponseWriter, r *http.Request, v *validator.Validator, key string, err error) {
+	if errors.Is(err, tax.ErrUnknownRegion) {
+		v.AddErrorCode(key, validator.CodeNotAllowed, err.Error())
+		app.failedValidationResponse(w, r, v)
 		return
 	}
 
-	app.logger.Info("Successfully converted HTML to PDF and sent to client")
+	app.serverErrorResponse(w, r, err)
 }
 
-func (app *application) createInvoice(w http.ResponseWriter, r *http.Request) {
-	var input *generate.InvoiceData
-	app.logger.Info("Creating invoice with the JSON body")
+// renderInvoiceImage renders the invoice template, screenshots it through
+// Gotenberg and encodes it as format ("png" or "jpeg"), degraded by the named
+// profile unless degrade is empty. The degradation is seeded so a seed still
+// reproduces the exact image.
+func (app *application) renderInvoiceImage(data *generate.InvoiceData, options *generate.RenderOptions, format, degrade string, seed int64) ([]byte, error) {
+	screenshot, err := app.render("png", generate.InvoiceTemplate, data, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if degrade == "" && format == "png" {
+		return screenshot, nil
+	}
 
-	err := app.readJSON(w, r, &input)
+	img, err := png.Decode(bytes.NewReader(screenshot))
 	if err != nil {
-		app.logger.Error("failed to decode invoice data", "error", err.Error())
-		app.badRequestResponse(w, r, err)
+		return nil, fmt.Errorf("failed to decode screenshot: %v", err)
+	}
+
+	if profile, ok := imaging.LookupProfile(degrade); ok {
+		img, err = imaging.Degrade(img, profile, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			return nil, fmt.Errorf("failed to degrade image: %v", err)
+		}
+	}
+
+	return imaging.Encode(img, format)
+}
+
+func (app *application) createFakeInvoice(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+	options, renderOptions := app.readFakeInvoiceOptions(qs, v)
+	format := app.readFormat(r, qs, v, "pdf", "json", "html", "png", "jpeg")
+	degrade := app.readString(qs, "degrade", "")
+	v.Check(!renderOptions.Annotate || format == "pdf", "annotations", "are only available for PDF output")
+	if degrade != "" {
+		_, knownProfile := imaging.LookupProfile(degrade)
+		v.Check(knownProfile, "degrade", fmt.Sprintf("must be one of %v", imaging.ProfileNames()))
+		v.Check(format == "png" || format == "jpeg", "degrade", "is only available for PNG and JPEG output")
+	}
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
 		return
 	}
 
-	app.logger.Info("Generating invoice HTML")
-	invoiceHtml, err := generate.GenerateInvoiceHtml(input)
+	app.logger.Info("Creating invoice with the following parameters: " +
+		fmt.Sprintf("seed=%v, paymentMethods=%v, vendorName=%v, accountNumber=%v, numberOfItems=%v, invoiceDate=%v, dueDate=%v, currency=%v, language=%v, locale=%v, format=%v",
+			options.Seed, options.PaymentMethods, options.VendorName, options.AccountNumber, options.NumberOfItems, options.InvoiceDate, options.DueDate, options.Currency, renderOptions.Language, renderOptions.Locale, format))
+
+	randomInvoice, err := generate.GenerateRandomInvoiceData(options)
 	if err != nil {
-		app.serverErrorResponse(w, r, fmt.Errorf("failed to create invoice.html file: %v", err))
+		app.fakeDocumentErrorResponse(w, r, v, taxRegionKey(options), err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("X-Seed", strconv.FormatInt(options.Seed, 10))
+
+	switch format {
+	case "json":
+		err := app.writeJSON(w, http.StatusOK, envelope{"invoice": randomInvoice}, headers)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+
+	case "png", "jpeg":
+		imageContent, err := app.renderInvoiceImage(&randomInvoice, renderOptions, format, degrade, options.Seed)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		app.writeDocument(w, r, "image/"+format, imageContent, headers)
 		return
 	}
 
-	app.logger.Info("Converting HTML to PDF")
-	pdfContent, err := convert.HtmlToPdfV2(invoiceHtml)
+	app.logger.Info("Rendering invoice to " + format + "...")
+	content, err := app.render(format, generate.InvoiceTemplate, &randomInvoice, renderOptions)
 	if err != nil {
-		app.serverErrorResponse(w, r, fmt.Errorf("failed to convert HTML to PDF: %v", err))
+		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	app.logger.Info("Sending PDF content to client")
-	w.Header().Set("Content-Type", "application/pdf")
-	w.Header().Set("Content-Length", fmt.Sprintf("%d", len(pdfContent)))
-	if _, err := io.Copy(w, bytes.NewReader(pdfContent)); err != nil {
-		app.serverErrorResponse(w, r, fmt.Errorf("failed to send PDF content to client: %v", err))
+	if renderOptions.Annotate {
+		annotations, err := annotate.Extract(content, annotate.Letter)
+		if err != nil {
+			app.serverErrorResponse(w, r, fmt.Errorf("failed to extract annotations: %v", err))
+			return
+		}
+
+		archive, err := annotatedInvoiceArchive(content, annotations)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		app.logger.Info("Sending annotated PDF to client...")
+		headers.Set("Content-Disposition", `attachment; filename="invoice.zip"`)
+		app.writeDocument(w, r, "application/zip", archive, headers)
 		return
 	}
 
-	app.logger.Info("Successfully created invoice and sent to client")
+	app.logger.Info("Sending " + format + " content to client...")
+	app.writeDocument(w, r, contentTypes[format], content, headers)
+}
+
+// maxLogoBytes limits the logo part of multipart invoice requests.
+const maxLogoBytes = 2 << 20
+
+// readInvoiceMultipart reads a multipart/form-data invoice request: an
+// "invoice" part holding the same JSON as a plain request, and an optional
+// "logo" file part whose content is returned as is.
+func (app *application) readInvoiceMultipart(w http.ResponseWriter, r *http.Request, dst *generate.InvoiceData) ([]byte, error) {
+	// the JSON part keeps its own 1MB limit, the logo gets maxLogoBytes
+	r.Body = http.MaxBytesReader(w, r.Body, 1_048_576+maxLogoBytes)
+
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	var logo []byte
+	var hasInvoice bool
+	for {
+		part, err := mr.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				return nil, fmt.Errorf("body must not be larger than %d bytes", maxBytesError.Limit)
+			}
+			return nil, err
+		}
+
+		switch part.FormName() {
+		case "invoice":
+			if err := app.decodeJSON(io.LimitReader(part, 1_048_576), dst); err != nil {
+				return nil, fmt.Errorf("invoice part: %w", err)
+			}
+			hasInvoice = true
+
+		case "logo":
+			logo, err = io.ReadAll(io.LimitReader(part, maxLogoBytes+1))
+			if err != nil {
+				return nil, err
+			}
+			if len(logo) > maxLogoBytes {
+				return nil, fmt.Errorf("logo must not be larger than %d bytes", maxLogoBytes)
+			}
+
+		default:
+			return nil, fmt.Errorf("body contains unknown part %q", part.FormName())
+		}
+	}
+
+	if !hasInvoice {
+		return nil, errors.New("body must contain an invoice part")
+	}
+
+	return logo, nil
+}
+
+// annotatedInvoiceArchive bundles a PDF with its field annotations.
+func annotatedInvoiceArchive(pdf []byte, annotations *annotate.Annotations) ([]byte, error) {
+	js, err := json.MarshalIndent(annotations, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := writeZipFile(zw, "invoice.pdf", pdf); err != nil {
+		return nil, err
+	}
+	if err := writeZipFile(zw, "invoice.annotations.json", js); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// readInvoice reads an invoice sent as JSON, or as multipart/form-data with
+// an optional logo upload that replaces CompanyLogo.
+func (app *application) readInvoice(w http.ResponseWriter, r *http.Request, v *validator.Validator, dst *generate.InvoiceData) error {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "multipart/form-data" {
+		return app.readJSON(w, r, dst)
+	}
+
+	logo, err := app.readInvoiceMultipart(w, r, dst)
+	if err != nil || logo == nil {
+		return err
+	}
+
+	dst.CompanyLogo, err = imaging.LogoDataURI(logo)
+	switch {
+	case errors.Is(err, imaging.ErrLogoTooLarge):
+		v.AddErrorCode("logo", validator.CodeOutOfRange, "must not be larger than 4096×4096 pixels")
+	case err != nil:
+		v.AddErrorCode("logo", validator.CodeNotAllowed, "must be a PNG, JPEG or SVG image")
+	}
+
+	return nil
+}
+
+func (app *application) createInvoice(w http.ResponseWriter, r *http.Request) {
+	createDocument(app, w, r, postedDocument[generate.InvoiceData]{
+		name:           "invoice",
+		template:       generate.InvoiceTemplate,
+		decode:         app.readInvoice,
+		validate:       generate.ValidateInvoice,
+		validateTotals: generate.ValidateTotals,
+		items:          func(d *generate.InvoiceData) *[]generate.InvoiceItem { return &d.Items },
+		logo:           func(d *generate.InvoiceData) *string { return &d.CompanyLogo },
+		regionKey:      func(d *generate.InvoiceData) string { return postedRegionKey(d.VendorInfo) },
+	})
 }
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/resource"
+)
+
+func TestCreateFakeInvoiceJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		accept string
+	}{
+		{"Format parameter", "?seed=7&numberOfItems=3&format=json", ""},
+		{"Accept header", "?seed=7&numberOfItems=3", "application/json"},
+	}
+
+	app := newTestApplication()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/v1/invoices/fake"+tt.query, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			app.createFakeInvoice(rr, req)
+
+			assert.Equal(t, rr.Code, http.StatusOK)
+			assert.Equal(t, rr.Header().Get("Content-Type"), "application/json")
+			assert.Equal(t, rr.Header().Get("X-Seed"), "7")
+
+			var response struct {
+				Invoice generate.InvoiceData `json:"invoice"`
+			}
+			decodeResponse(t, rr, &response)
+
+			assert.Equal(t, len(response.Invoice.Items), 3)
+			assert.Equal(t, response.Invoice.Total, response.Invoice.Subtotal-response.Invoice.DiscountTotal+response.Invoice.TaxTotal)
+		})
+	}
+}
+
+func TestCreateFakeInvoiceInvalidOptions(t *testing.T) {
+	app := newTestApplication()
+
+	testInvalidOptions(t, app.createFakeInvoice, http.MethodGet, "/v1/invoices/fake", []invalidOptionsTest{
+		{"Unknown format", "?format=docx", "format"},
+		{"Unknown US state", "?customerRegion=ZZ&format=json", "customerRegion"},
+		{"Indian customer without state", "?vendorCountry=IN&vendorRegion=KA&customerCountry=IN&format=json", "customerRegion"},
+		{"Indian vendor without state", "?vendorCountry=IN&customerCountry=IN&customerRegion=KA&format=json", "vendorRegion"},
+		{"Customer country", "?customerCountry=USA&format=json", "customerCountry"},
+		{"Customer region", "?customerRegion=CALI&format=json", "customerRegion"},
+		{"Vendor region", "?vendorRegion=CALI&format=json", "vendorRegion"},
+		{"No items", "?numberOfItems=0&format=json", "numberOfItems"},
+		{"Too many items", "?numberOfItems=21&format=json", "numberOfItems"},
+		{"Unknown degrade profile", "?format=png&degrade=crumpled", "degrade"},
+		{"Degraded PDF", "?format=pdf&degrade=scan", "degrade"},
+	})
+}
+
+func TestCreateInvoiceValidation(t *testing.T) {
+	app := newTestApplication()
+
+	testValidation(t, app.createInvoice, "/v1/invoices", []validationTest{
+		{"Empty invoice", `{}`, []string{"InvoiceNumber", "VendorInfo.Name", "Items"}},
+		{"Inconsistent total", `{
+			"InvoiceNumber": "1", "InvoiceDate": "2024-03-05", "DueDate": "2024-04-04", "Currency": "USD",
+			"VendorInfo": {"Name": "Globex"}, "CustomerInfo": {"Name": "Acme Corp."},
+			"Items": [{"Description": "Consulting", "Quantity": 2, "UnitPrice": 500}],
+			"Total": 999
+		}`, []string{"Total"}},
+		{"Indian vendor without state", `{
+			"InvoiceNumber": "1", "InvoiceDate": "2024-03-05", "DueDate": "2024-04-04", "Currency": "INR",
+			"VendorInfo": {"Name": "Globex", "Country": "IN"}, "CustomerInfo": {"Name": "Acme Corp.", "Country": "IN", "Region": "KA"},
+			"Items": [{"Description": "Consulting", "Quantity": 2, "UnitPrice": 500}]
+		}`, []string{"VendorInfo.Region"}},
+		{"Indian customer without state", `{
+			"InvoiceNumber": "1", "InvoiceDate": "2024-03-05", "DueDate": "2024-04-04", "Currency": "INR",
+			"VendorInfo": {"Name": "Globex", "Country": "IN", "Region": "KA"}, "CustomerInfo": {"Name": "Acme Corp.", "Country": "IN"},
+			"Items": [{"Description": "Consulting", "Quantity": 2, "UnitPrice": 500}]
+		}`, []string{"CustomerInfo.Region"}},
+	})
+}
+
+func TestCreateInvoiceMultipart(t *testing.T) {
+	invoice := `{
+		"InvoiceNumber": "1", "InvoiceDate": "2024-03-05", "DueDate": "2024-04-04", "Currency": "USD",
+		"VendorInfo": {"Name": "Globex"}, "CustomerInfo": {"Name": "Acme Corp."},
+		"Items": [{"Description": "Consulting", "Quantity": 2, "UnitPrice": 500}]
+	}`
+
+	tests := []struct {
+		name   string
+		parts  map[string]string
+		status int
+	}{
+		{"Unsupported logo", map[string]string{"invoice": invoice, "logo": "GIF89a"}, http.StatusUnprocessableEntity},
+		{"Missing invoice", map[string]string{"logo": "<svg></svg>"}, http.StatusBadRequest},
+		{"Unknown part", map[string]string{"invoice": invoice, "signature": "x"}, http.StatusBadRequest},
+	}
+
+	app := newTestApplication()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body bytes.Buffer
+			mw := multipart.NewWriter(&body)
+			for name, content := range tt.parts {
+				var part io.Writer
+				var err error
+				if name == "invoice" {
+					part, err = mw.CreateFormField(name)
+				} else {
+					part, err = mw.CreateFormFile(name, name+".bin")
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				io.WriteString(part, content)
+			}
+			mw.Close()
+
+			req := httptest.NewRequest(http.MethodPost, "/v1/invoices", &body)
+			req.Header.Set("Content-Type", mw.FormDataContentType())
+			rr := httptest.NewRecorder()
+
+			app.createInvoice(rr, req)
+
+			assert.Equal(t, rr.Code, tt.status)
+		})
+	}
+}
+
+func TestCreateInvoiceResourcePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		logo    string
+		message string
+	}{
+		{"Metadata service", "http://169.254.169.254/latest/meta-data/", resource.ErrPrivateAddress.Error()},
+		{"Cluster service", "http://10.0.0.12:3000/forms/chromium/convert/html", resource.ErrPrivateAddress.Error()},
+		{"Local file", "file:///etc/passwd", resource.ErrScheme.Error()},
+	}
+
+	app := newTestApplication()
+	app.config.resources = resource.Policy{AllowedDomains: []string{"*"}, BlockPrivate: true, Inline: true}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{
+				"CompanyLogo": "` + tt.logo + `",
+				"InvoiceNumber": "1", "InvoiceDate": "2024-03-05", "DueDate": "2024-04-04", "Currency": "USD",
+				"VendorInfo": {"Name": "Globex"}, "CustomerInfo": {"Name": "Acme Corp."},
+				"Items": [{"Description": "Consulting", "Quantity": 2, "UnitPrice": 500}]
+			}`
+			rr := serve(app.createInvoice, http.MethodPost, "/v1/invoices", body)
+
+			assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+
+			var response failedValidation
+			decodeResponse(t, rr, &response)
+			assert.Equal(t, response.Error["CompanyLogo"], tt.message)
+		})
+	}
+}
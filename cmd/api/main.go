@@ -8,6 +8,9 @@ import (
 	"os"
 	"strings"
 	"time"
+
+	"tools.lucasfaria.dev/internal/resource"
+	"tools.lucasfaria.dev/internal/tax"
 )
 
 const version = "1.0.0"
@@ -24,11 +27,16 @@ type config struct {
 	cors struct {
 		trustedOrigins []string
 	}
+	batch struct {
+		concurrency int
+	}
+	resources resource.Policy
 }
 
 type application struct {
-	config config
-	logger *slog.Logger
+	config    config
+	logger    *slog.Logger
+	taxEngine *tax.Engine
 }
 
 func main() {
@@ -39,6 +47,17 @@ func main() {
 	flag.Float64Var(&cfg.limiter.rps, "limiter-rps", 2, "Rate limiter maximum requests per second")
 	flag.IntVar(&cfg.limiter.burst, "limiter-burst", 4, "Rate limiter maximum burst")
 	flag.BoolVar(&cfg.limiter.enabled, "limiter-enabled", true, "Enable rate limiter")
+	flag.IntVar(&cfg.batch.concurrency, "batch-concurrency", 4, "Maximum concurrent Gotenberg renders per batch request")
+
+	cfg.resources.AllowedDomains = []string{"*"}
+	flag.Func("resources-allowed-domains", "Domains invoices may load images from, space separated; * for any (default *)", func(val string) error {
+		cfg.resources.AllowedDomains = strings.Fields(val)
+		return nil
+	})
+	flag.BoolVar(&cfg.resources.BlockPrivate, "resources-block-private", true, "Refuse images on private network addresses")
+	flag.BoolVar(&cfg.resources.Inline, "resources-inline", true, "Fetch remote images and embed them instead of letting Gotenberg fetch them")
+	flag.Int64Var(&cfg.resources.MaxBytes, "resources-max-bytes", 2<<20, "Maximum size of a fetched image")
+	flag.DurationVar(&cfg.resources.Timeout, "resources-timeout", 5*time.Second, "Timeout for fetching an image")
 	flag.Parse()
 
 	cfg.cors.trustedOrigins = strings.Fields(corsTrustedOrigins)
@@ -46,8 +65,9 @@ func main() {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	app := &application{
-		config: cfg,
-		logger: logger,
+		config:    cfg,
+		logger:    logger,
+		taxEngine: tax.NewEngine(),
 	}
 
 	srv := &http.Server{
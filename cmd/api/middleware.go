@@ -88,6 +88,7 @@ func (app *application) enableCORS(next http.Handler) http.Handler {
 			for _, allowedOrigin := range app.config.cors.trustedOrigins {
 				if origin == allowedOrigin {
 					w.Header().Set("Access-Control-Allow-Origin", origin)
+					w.Header().Set("Access-Control-Expose-Headers", "X-Seed")
 					break
 				}
 			}
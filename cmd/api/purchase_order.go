@@ -0,0 +1,163 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+// matchSetArchive renders the three documents of a match set to PDF and
+// bundles them with the list of planted discrepancies.
+func (app *application) matchSetArchive(set *generate.MatchSet, options *generate.RenderOptions) ([]byte, error) {
+	po, err := app.render("pdf", generate.PurchaseOrderTemplate, &set.PurchaseOrder, options)
+	if err != nil {
+		return nil, err
+	}
+	gr, err := app.render("pdf", generate.GoodsReceiptTemplate, &set.GoodsReceipt, options)
+	if err != nil {
+		return nil, err
+	}
+	invoice, err := app.render("pdf", generate.InvoiceTemplate, &set.Invoice, options)
+	if err != nil {
+		return nil, err
+	}
+
+	discrepancies, err := json.MarshalIndent(set.Discrepancies, "", "\t")
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, file := range []struct {
+		name    string
+		content []byte
+	}{
+		{"purchase-order.pdf", po},
+		{"goods-receipt.pdf", gr},
+		{"invoice.pdf", invoice},
+		{"discrepancies.json", discrepancies},
+	} {
+		if err := writeZipFile(zw, file.name, file.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// createFakePurchaseOrder generates a purchase order from the fake invoice
+// options. With match=true it generates the whole three-way match set
+// instead: the order, its goods receipt and invoice, optionally with
+// discrepancies planted between them.
+func (app *application) createFakePurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+	options, renderOptions := app.readFakeInvoiceOptions(qs, v)
+	match := app.readBool(qs, "match", false, v)
+	discrepancies := app.readCSV(qs, "discrepancies", nil)
+	for i, discrepancy := range discrepancies {
+		discrepancies[i] = strings.ToLower(discrepancy)
+	}
+
+	var format string
+	if match {
+		format = app.readFormat(r, qs, v, "pdf", "json")
+	} else {
+		format = app.readFormat(r, qs, v, "pdf", "json", "html")
+	}
+	v.Check(validator.PermittedValues(discrepancies, generate.Discrepancies), "discrepancies", fmt.Sprintf("must be list of %v", generate.Discrepancies))
+	v.Check(len(discrepancies) == 0 || match, "discrepancies", "are only available with match")
+	v.Check(!renderOptions.Annotate, "annotations", "are only available for invoices")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	app.logger.Info("Creating purchase order with the following parameters: " +
+		fmt.Sprintf("seed=%v, vendorName=%v, numberOfItems=%v, orderDate=%v, currency=%v, match=%v, discrepancies=%v, format=%v",
+			options.Seed, options.VendorName, options.NumberOfItems, options.InvoiceDate, options.Currency, match, discrepancies, format))
+
+	headers := make(http.Header)
+	headers.Set("X-Seed", strconv.FormatInt(options.Seed, 10))
+
+	if match {
+		set, err := generate.GenerateMatchSet(options, discrepancies)
+		if err != nil {
+			app.fakeDocumentErrorResponse(w, r, v, taxRegionKey(options), err)
+			return
+		}
+
+		if format == "json" {
+			err := app.writeJSON(w, http.StatusOK, envelope{
+				"purchaseOrder": set.PurchaseOrder,
+				"goodsReceipt":  set.GoodsReceipt,
+				"invoice":       set.Invoice,
+				"discrepancies": set.Discrepancies,
+			}, headers)
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		archive, err := app.matchSetArchive(&set, renderOptions)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		headers.Set("Content-Disposition", `attachment; filename="three-way-match.zip"`)
+		app.writeDocument(w, r, "application/zip", archive, headers)
+		return
+	}
+
+	po, err := generate.GenerateRandomPurchaseOrderData(options)
+	if err != nil {
+		app.fakeDocumentErrorResponse(w, r, v, taxRegionKey(options), err)
+		return
+	}
+
+	switch format {
+	case "json":
+		err := app.writeJSON(w, http.StatusOK, envelope{"purchaseOrder": po}, headers)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+
+	}
+
+	content, err := app.render(format, generate.PurchaseOrderTemplate, &po, renderOptions)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.logger.Info("Sending " + format + " content to client...")
+	app.writeDocument(w, r, contentTypes[format], content, headers)
+}
+
+func (app *application) createPurchaseOrder(w http.ResponseWriter, r *http.Request) {
+	createDocument(app, w, r, postedDocument[generate.PurchaseOrderData]{
+		name:           "purchase order",
+		template:       generate.PurchaseOrderTemplate,
+		validate:       generate.ValidatePurchaseOrder,
+		validateTotals: generate.ValidatePurchaseOrderTotals,
+		items:          func(d *generate.PurchaseOrderData) *[]generate.InvoiceItem { return &d.Items },
+		logo:           func(d *generate.PurchaseOrderData) *string { return &d.CompanyLogo },
+		regionKey:      func(d *generate.PurchaseOrderData) string { return postedRegionKey(d.VendorInfo) },
+	})
+}
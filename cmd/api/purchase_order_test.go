@@ -0,0 +1,60 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/generate"
+)
+
+func TestCreateFakePurchaseOrderMatchJSON(t *testing.T) {
+	app := newTestApplication()
+
+	rr := serve(app.createFakePurchaseOrder, http.MethodGet, "/v1/purchase-orders/fake?seed=7&match=true&discrepancies=Price&format=json", "")
+
+	assert.Equal(t, rr.Code, http.StatusOK)
+	assert.Equal(t, rr.Header().Get("X-Seed"), "7")
+
+	var response struct {
+		PurchaseOrder generate.PurchaseOrderData `json:"purchaseOrder"`
+		GoodsReceipt  generate.GoodsReceiptData  `json:"goodsReceipt"`
+		Invoice       generate.InvoiceData       `json:"invoice"`
+		Discrepancies []generate.Discrepancy     `json:"discrepancies"`
+	}
+	decodeResponse(t, rr, &response)
+
+	assert.Equal(t, response.GoodsReceipt.PONumber, response.PurchaseOrder.PONumber)
+	assert.Equal(t, response.Invoice.PONumber, response.PurchaseOrder.PONumber)
+	assert.Equal(t, len(response.Discrepancies), 1)
+	assert.Equal(t, response.Discrepancies[0].Type, generate.DiscrepancyPrice)
+}
+
+func TestCreateFakePurchaseOrderInvalidOptions(t *testing.T) {
+	app := newTestApplication()
+
+	testInvalidOptions(t, app.createFakePurchaseOrder, http.MethodGet, "/v1/purchase-orders/fake", []invalidOptionsTest{
+		{"Unknown discrepancy", "?match=true&discrepancies=tax", "discrepancies"},
+		{"Discrepancies without match", "?discrepancies=price", "discrepancies"},
+		{"HTML match set", "?match=true&format=html", "format"},
+	})
+}
+
+func TestCreatePurchaseOrderValidation(t *testing.T) {
+	app := newTestApplication()
+
+	testValidation(t, app.createPurchaseOrder, "/v1/purchase-orders", []validationTest{
+		{"Empty purchase order", `{}`, []string{"PONumber", "OrderDate", "VendorInfo.Name", "Items"}},
+		{"Delivery before order", `{
+			"PONumber": "PO-1", "OrderDate": "2024-03-05", "DeliveryDate": "2024-03-01", "Currency": "USD",
+			"VendorInfo": {"Name": "Globex"}, "CustomerInfo": {"Name": "Acme Corp."},
+			"Items": [{"Description": "Widgets", "Quantity": 2, "UnitPrice": 500}]
+		}`, []string{"DeliveryDate"}},
+		{"Inconsistent total", `{
+			"PONumber": "PO-1", "OrderDate": "2024-03-05", "Currency": "USD",
+			"VendorInfo": {"Name": "Globex"}, "CustomerInfo": {"Name": "Acme Corp."},
+			"Items": [{"Description": "Widgets", "Quantity": 2, "UnitPrice": 500}],
+			"Total": 999
+		}`, []string{"Total"}},
+	})
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/convert"
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+// readFakeReceiptOptions reads the query string of the fake receipt endpoint.
+// Like for invoices, anything that is not given is drawn from the seed.
+func (app *application) readFakeReceiptOptions(qs url.Values, v *validator.Validator) (*generate.GenerateReceiptOptions, *generate.RenderOptions) {
+	now := time.Now()
+	lang := app.readLanguage(qs, "language", language.AmericanEnglish, v)
+	locale := app.readLocale(qs, "locale", lang, v)
+
+	options := &generate.GenerateReceiptOptions{
+		Seed:            app.readInt64(qs, "seed", now.UnixNano(), v),
+		MerchantName:    app.readString(qs, "merchantName", ""),
+		NumberOfItems:   app.readCount(qs, "numberOfItems", v),
+		Date:            app.readDate(qs, "date", now, v).Format(time.DateOnly),
+		Currency:        strings.ToLower(app.readString(qs, "currency", "usd")),
+		MerchantCountry: app.readString(qs, "merchantCountry", "US"),
+		MerchantRegion:  app.readString(qs, "merchantRegion", ""),
+		Tender:          strings.ToLower(app.readString(qs, "tender", "")),
+		TaxEngine:       app.taxEngine,
+	}
+
+	v.Struct(options)
+
+	renderOptions := &generate.RenderOptions{
+		Language: lang,
+		Locale:   locale,
+	}
+
+	return options, renderOptions
+}
+
+func (app *application) createFakeReceipt(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+	options, renderOptions := app.readFakeReceiptOptions(qs, v)
+	format := app.readFormat(r, qs, v, "pdf", "json", "html")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	app.logger.Info("Creating receipt with the following parameters: " +
+		fmt.Sprintf("seed=%v, merchantName=%v, numberOfItems=%v, date=%v, currency=%v, tender=%v, language=%v, locale=%v, format=%v",
+			options.Seed, options.MerchantName, options.NumberOfItems, options.Date, options.Currency, options.Tender, renderOptions.Language, renderOptions.Locale, format))
+
+	randomReceipt, err := generate.GenerateRandomReceiptData(options)
+	if err != nil {
+		app.fakeDocumentErrorResponse(w, r, v, "merchantRegion", err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("X-Seed", strconv.FormatInt(options.Seed, 10))
+
+	switch format {
+	case "json":
+		err := app.writeJSON(w, http.StatusOK, envelope{"receipt": randomReceipt}, headers)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+
+	}
+
+	content, err := app.render(format, generate.ReceiptTemplate, &randomReceipt, renderOptions, convert.ReceiptPage)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.logger.Info("Sending " + format + " content to client...")
+	app.writeDocument(w, r, contentTypes[format], content, headers)
+}
+
+// readReceipt reads a receipt sent as JSON. Whatever card number the client
+// sent, only the last digits are printed.
+func (app *application) readReceipt(w http.ResponseWriter, r *http.Request, v *validator.Validator, dst *generate.ReceiptData) error {
+	err := app.readJSON(w, r, dst)
+	dst.Tender.MaskedPAN = generate.MaskPAN(dst.Tender.MaskedPAN)
+	return err
+}
+
+func (app *application) createReceipt(w http.ResponseWriter, r *http.Request) {
+	createDocument(app, w, r, postedDocument[generate.ReceiptData]{
+		name:           "receipt",
+		template:       generate.ReceiptTemplate,
+		page:           []convert.RequestOption{convert.ReceiptPage},
+		decode:         app.readReceipt,
+		validate:       generate.ValidateReceipt,
+		validateTotals: generate.ValidateReceiptTotals,
+		items:          func(d *generate.ReceiptData) *[]generate.InvoiceItem { return &d.Items },
+		regionKey:      func(*generate.ReceiptData) string { return "Merchant.Region" },
+	})
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/generate"
+)
+
+func TestCreateFakeReceiptJSON(t *testing.T) {
+	app := newTestApplication()
+
+	rr := serve(app.createFakeReceipt, http.MethodGet, "/v1/receipts/fake?seed=7&numberOfItems=3&tender=cash&format=json", "")
+
+	assert.Equal(t, rr.Code, http.StatusOK)
+	assert.Equal(t, rr.Header().Get("X-Seed"), "7")
+
+	var response struct {
+		Receipt generate.ReceiptData `json:"receipt"`
+	}
+	decodeResponse(t, rr, &response)
+
+	assert.Equal(t, len(response.Receipt.Items), 3)
+	assert.Equal(t, response.Receipt.Tender.Type, generate.TenderCash)
+	assert.Equal(t, response.Receipt.Tender.Tendered-response.Receipt.Tender.Change, response.Receipt.Total)
+}
+
+func TestCreateFakeReceiptInvalidOptions(t *testing.T) {
+	app := newTestApplication()
+
+	testInvalidOptions(t, app.createFakeReceipt, http.MethodGet, "/v1/receipts/fake", []invalidOptionsTest{
+		{"Unknown tender", "?tender=cheque", "tender"},
+		{"Too many items", "?numberOfItems=99", "numberOfItems"},
+		{"PNG receipt", "?format=png", "format"},
+		{"Unknown region", "?merchantRegion=XX&format=json", "merchantRegion"},
+	})
+}
+
+func TestCreateReceiptValidation(t *testing.T) {
+	app := newTestApplication()
+
+	testValidation(t, app.createReceipt, "/v1/receipts", []validationTest{
+		{"Empty receipt", `{}`, []string{"TransactionID", "Merchant.Name", "Items", "Tender.Type"}},
+		{"Malformed time", `{
+			"Merchant": {"Name": "Corner Market"}, "TransactionID": "1", "Date": "2024-03-05", "Time": "9h30", "Currency": "USD",
+			"Items": [{"Description": "Apple", "Quantity": 3, "UnitPrice": 99}],
+			"Tender": {"Type": "cash", "Tendered": 100}
+		}`, []string{"Time"}},
+		{"Short cash", `{
+			"Merchant": {"Name": "Corner Market"}, "TransactionID": "1", "Date": "2024-03-05", "Currency": "USD",
+			"Items": [{"Description": "Apple", "Quantity": 3, "UnitPrice": 99}],
+			"Tender": {"Type": "cash", "Tendered": 100}
+		}`, []string{"Tender.Tendered"}},
+	})
+}
@@ -13,8 +13,20 @@ func (app *application) routes() http.Handler {
 	router.MethodNotAllowed = http.HandlerFunc(app.methodNotAllowedResponse)
 
 	router.HandlerFunc(http.MethodGet, "/v1/healthcheck", app.healthcheckHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/currencies", app.listCurrenciesHandler)
 	router.HandlerFunc(http.MethodGet, "/v1/invoices/fake", app.createFakeInvoice)
+	router.HandlerFunc(http.MethodPost, "/v1/invoices/fake/batch", app.createFakeInvoiceBatch)
 	router.HandlerFunc(http.MethodPost, "/v1/invoices", app.createInvoice)
+	router.HandlerFunc(http.MethodGet, "/v1/receipts/fake", app.createFakeReceipt)
+	router.HandlerFunc(http.MethodPost, "/v1/receipts", app.createReceipt)
+	router.HandlerFunc(http.MethodGet, "/v1/purchase-orders/fake", app.createFakePurchaseOrder)
+	router.HandlerFunc(http.MethodPost, "/v1/purchase-orders", app.createPurchaseOrder)
+	router.HandlerFunc(http.MethodGet, "/v1/credit-notes/fake", app.createFakeCreditNote)
+	router.HandlerFunc(http.MethodPost, "/v1/credit-notes", app.createCreditNote)
+	router.HandlerFunc(http.MethodGet, "/v1/statements/fake", app.createFakeStatement)
+	router.HandlerFunc(http.MethodGet, "/v1/tax-forms/w9/fake", app.createFakeW9)
+	router.HandlerFunc(http.MethodGet, "/v1/tax-forms/w8ben/fake", app.createFakeW8BEN)
+	router.HandlerFunc(http.MethodGet, "/v1/tax-forms/w8bene/fake", app.createFakeW8BENE)
 
 	return app.recoverPanic(app.rateLimit(app.enableCORS(router)))
 }
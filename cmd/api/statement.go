@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+const maxStatementPayments = 100
+
+// readStatementPayments reads the invoices a statement is paid for as a list
+// of invoice numbers, each optionally followed by the amount paid in minor
+// units, such as 10001:125000,10002.
+func (app *application) readStatementPayments(qs url.Values, key string, v *validator.Validator) []generate.StatementPayment {
+	payments := []generate.StatementPayment{}
+
+	for _, entry := range app.readCSV(qs, key, nil) {
+		number, amount, hasAmount := strings.Cut(strings.TrimSpace(entry), ":")
+		payment := generate.StatementPayment{InvoiceNumber: number}
+
+		if hasAmount {
+			i, err := strconv.ParseInt(amount, 10, 64)
+			if err != nil || i <= 0 {
+				v.AddError(key, "must be a list of invoice numbers, each optionally followed by a positive amount in minor units, such as 10001:125000")
+				return nil
+			}
+			if i > money.MaxAmount {
+				v.AddErrorCode(key, validator.CodeOutOfRange, fmt.Sprintf("must not contain amounts over %d", money.MaxAmount))
+				return nil
+			}
+			payment.Amount = i
+		}
+
+		if number == "" || len(number) > 64 {
+			v.AddError(key, "must only contain invoice numbers of 1 to 64 characters")
+			return nil
+		}
+
+		payments = append(payments, payment)
+	}
+
+	v.Check(len(payments) <= maxStatementPayments, key, fmt.Sprintf("must not contain more than %d invoices", maxStatementPayments))
+
+	return payments
+}
+
+// readFakeStatementOptions reads the query string of the fake statement
+// endpoint. The period defaults to the month up to today.
+func (app *application) readFakeStatementOptions(qs url.Values, v *validator.Validator) (*generate.GenerateStatementOptions, *generate.RenderOptions) {
+	now := time.Now()
+	lang := app.readLanguage(qs, "language", language.AmericanEnglish, v)
+	locale := app.readLocale(qs, "locale", lang, v)
+
+	end := app.readDate(qs, "to", now, v)
+	start := app.readDate(qs, "from", end.AddDate(0, -1, 1), v)
+	v.Check(!end.Before(start), "to", "must not be before from")
+
+	options := &generate.GenerateStatementOptions{
+		Seed:                 app.readInt64(qs, "seed", now.UnixNano(), v),
+		AccountHolder:        app.readString(qs, "accountHolder", ""),
+		AccountNumber:        app.readInt64(qs, "accountNumber", 0, v),
+		Country:              app.readString(qs, "country", "US"),
+		Currency:             strings.ToLower(app.readString(qs, "currency", "usd")),
+		PeriodStart:          start.Format(time.DateOnly),
+		PeriodEnd:            end.Format(time.DateOnly),
+		NumberOfTransactions: app.readCount(qs, "numberOfTransactions", v),
+		PayerName:            app.readString(qs, "payerName", ""),
+		Payments:             app.readStatementPayments(qs, "invoices", v),
+	}
+
+	v.Struct(options)
+
+	renderOptions := &generate.RenderOptions{
+		Language: lang,
+		Locale:   locale,
+	}
+
+	return options, renderOptions
+}
+
+// createFakeStatement generates a bank statement, crediting a payment for
+// each invoice number given in invoices so it can be reconciled against
+// them.
+func (app *application) createFakeStatement(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+	options, renderOptions := app.readFakeStatementOptions(qs, v)
+	format := app.readFormat(r, qs, v, "pdf", "json", "html")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	app.logger.Info("Creating statement with the following parameters: " +
+		fmt.Sprintf("seed=%v, accountHolder=%v, from=%v, to=%v, currency=%v, numberOfTransactions=%v, invoices=%v, format=%v",
+			options.Seed, options.AccountHolder, options.PeriodStart, options.PeriodEnd, options.Currency, options.NumberOfTransactions, len(options.Payments), format))
+
+	statement := generate.GenerateRandomStatementData(options)
+
+	headers := make(http.Header)
+	headers.Set("X-Seed", strconv.FormatInt(options.Seed, 10))
+
+	switch format {
+	case "json":
+		err := app.writeJSON(w, http.StatusOK, envelope{"statement": statement}, headers)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+
+	}
+
+	content, err := app.render(format, generate.StatementTemplate, &statement, renderOptions)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.logger.Info("Sending " + format + " content to client...")
+	app.writeDocument(w, r, contentTypes[format], content, headers)
+}
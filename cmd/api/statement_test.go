@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/generate"
+)
+
+func TestCreateFakeStatementJSON(t *testing.T) {
+	app := newTestApplication()
+
+	rr := serve(app.createFakeStatement, http.MethodGet, "/v1/statements/fake?seed=7&from=2024-03-01&to=2024-03-31&numberOfTransactions=5&invoices=10001:125000,10002&format=json", "")
+
+	assert.Equal(t, rr.Code, http.StatusOK)
+	assert.Equal(t, rr.Header().Get("X-Seed"), "7")
+
+	var response struct {
+		Statement generate.StatementData `json:"statement"`
+	}
+	decodeResponse(t, rr, &response)
+
+	assert.Equal(t, response.Statement.PeriodStart, "2024-03-01")
+	assert.Equal(t, response.Statement.PeriodEnd, "2024-03-31")
+	assert.Equal(t, len(response.Statement.Transactions), 7)
+
+	paid := map[string]int64{}
+	for _, transaction := range response.Statement.Transactions {
+		if transaction.InvoiceNumber != "" {
+			paid[transaction.InvoiceNumber] = transaction.Credit
+		}
+	}
+	assert.Equal(t, len(paid), 2)
+	assert.Equal(t, paid["10001"], int64(125000))
+}
+
+func TestCreateFakeStatementInvalidOptions(t *testing.T) {
+	app := newTestApplication()
+
+	testInvalidOptions(t, app.createFakeStatement, http.MethodGet, "/v1/statements/fake", []invalidOptionsTest{
+		{"Period ends before it starts", "?from=2024-03-31&to=2024-03-01", "to"},
+		{"Malformed amount", "?invoices=10001:12.50", "invoices"},
+		{"Amount too large", "?invoices=10001:9223372036854775807", "invoices"},
+		{"Empty invoice number", "?invoices=10001,,10002", "invoices"},
+		{"No transactions", "?numberOfTransactions=0", "numberOfTransactions"},
+		{"Too many transactions", "?numberOfTransactions=500", "numberOfTransactions"},
+	})
+}
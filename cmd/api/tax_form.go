@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/generate"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+// taxFormRenderOptions render the IRS forms, which only exist in English.
+var taxFormRenderOptions = &generate.RenderOptions{
+	Language: language.AmericanEnglish,
+	Locale:   language.AmericanEnglish,
+}
+
+// checkTaxFormVendor checks the vendor of the options is one that files
+// form: US persons file a W-9, foreign individuals a W-8BEN and foreign
+// entities a W-8BEN-E. Vendors drawn at random are companies, so a W-8BEN
+// needs the name of the individual in vendorName.
+func checkTaxFormVendor(v *validator.Validator, form string, options *generate.GenerateInvoiceOptions) {
+	us := strings.ToUpper(options.VendorCountry) == "US"
+
+	switch form {
+	case generate.TaxFormW9:
+		v.Check(us, "vendorCountry", "must be US for a W-9, foreign vendors file a W-8BEN or W-8BEN-E")
+	case generate.TaxFormW8BEN:
+		v.Check(!us, "vendorCountry", "must not be US for a W-8BEN, US vendors file a W-9")
+		v.Check(generate.IndividualName(options.VendorName), "vendorName", "must be the name of an individual for a W-8BEN, entities file a W-8BEN-E")
+	case generate.TaxFormW8BENE:
+		v.Check(!us, "vendorCountry", "must not be US for a W-8BEN-E, US vendors file a W-9")
+	}
+}
+
+// fakeTaxFormData fills form for the vendor of the options, the same vendor
+// GenerateRandomInvoiceData draws from them.
+func fakeTaxFormData(form string, options *generate.GenerateInvoiceOptions) any {
+	switch form {
+	case generate.TaxFormW8BEN:
+		data := generate.GenerateRandomW8BENData(options)
+		return &data
+	case generate.TaxFormW8BENE:
+		data := generate.GenerateRandomW8BENEData(options)
+		return &data
+	}
+
+	data := generate.GenerateRandomW9Data(options)
+	return &data
+}
+
+// taxFormTemplates are the templates of the forms.
+var taxFormTemplates = map[string]string{
+	generate.TaxFormW9:     generate.W9Template,
+	generate.TaxFormW8BEN:  generate.W8BENTemplate,
+	generate.TaxFormW8BENE: generate.W8BENETemplate,
+}
+
+func (app *application) createFakeW9(w http.ResponseWriter, r *http.Request) {
+	app.createFakeTaxForm(w, r, generate.TaxFormW9)
+}
+
+func (app *application) createFakeW8BEN(w http.ResponseWriter, r *http.Request) {
+	app.createFakeTaxForm(w, r, generate.TaxFormW8BEN)
+}
+
+func (app *application) createFakeW8BENE(w http.ResponseWriter, r *http.Request) {
+	app.createFakeTaxForm(w, r, generate.TaxFormW8BENE)
+}
+
+// createFakeTaxForm fills form for the vendor of the fake invoice options, so
+// the same query string gives an invoice and the tax form of its vendor.
+func (app *application) createFakeTaxForm(w http.ResponseWriter, r *http.Request, form string) {
+	v := validator.New()
+
+	qs := r.URL.Query()
+	if form != generate.TaxFormW9 && qs.Get("vendorCountry") == "" {
+		// the invoice default, US, is the one country the W-8 forms are never for
+		qs.Set("vendorCountry", "GB")
+	}
+	options, renderOptions := app.readFakeInvoiceOptions(qs, v)
+	format := app.readFormat(r, qs, v, "pdf", "json", "html")
+	checkTaxFormVendor(v, form, options)
+	v.Check(!renderOptions.Annotate, "annotations", "are only available for invoices")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v)
+		return
+	}
+
+	app.logger.Info("Creating tax form with the following parameters: " +
+		fmt.Sprintf("form=%v, seed=%v, vendorName=%v, vendorCountry=%v, createdAt=%v, format=%v",
+			form, options.Seed, options.VendorName, options.VendorCountry, options.InvoiceDate, format))
+
+	data := fakeTaxFormData(form, options)
+
+	headers := make(http.Header)
+	headers.Set("X-Seed", strconv.FormatInt(options.Seed, 10))
+
+	switch format {
+	case "json":
+		err := app.writeJSON(w, http.StatusOK, envelope{form: data}, headers)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+
+	}
+
+	content, err := app.render(format, taxFormTemplates[form], data, taxFormRenderOptions)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.logger.Info("Sending " + format + " content to client...")
+	app.writeDocument(w, r, contentTypes[format], content, headers)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"net/http"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/generate"
+)
+
+func TestCreateFakeW9JSON(t *testing.T) {
+	app := newTestApplication()
+
+	query := "?seed=7&vendorName=Globex+LLC&createdAt=2024-03-05"
+
+	rr := serve(app.createFakeW9, http.MethodGet, "/v1/tax-forms/w9/fake"+query+"&format=json", "")
+
+	assert.Equal(t, rr.Code, http.StatusOK)
+	assert.Equal(t, rr.Header().Get("X-Seed"), "7")
+
+	var response struct {
+		W9 generate.W9Data `json:"w9"`
+	}
+	decodeResponse(t, rr, &response)
+
+	// the invoice of the same query is billed by the vendor of the form
+	rr = serve(app.createFakeInvoice, http.MethodGet, "/v1/invoices/fake"+query+"&format=json", "")
+
+	var invoiceResponse struct {
+		Invoice generate.InvoiceData `json:"invoice"`
+	}
+	decodeResponse(t, rr, &invoiceResponse)
+
+	assert.Equal(t, response.W9.Name, "Globex LLC")
+	assert.Equal(t, response.W9.TaxClassification, "LLC")
+	assert.Equal(t, response.W9.StreetAddress, invoiceResponse.Invoice.VendorInfo.StreetAddress)
+	assert.Equal(t, response.W9.CityStateZip, invoiceResponse.Invoice.VendorInfo.CityStateZip)
+}
+
+func TestCreateFakeW8BENJSON(t *testing.T) {
+	app := newTestApplication()
+
+	rr := serve(app.createFakeW8BEN, http.MethodGet, "/v1/tax-forms/w8ben/fake?seed=7&vendorName=Jane+Doe&format=json", "")
+
+	assert.Equal(t, rr.Code, http.StatusOK)
+
+	var response struct {
+		W8BEN generate.W8BENData `json:"w8ben"`
+	}
+	decodeResponse(t, rr, &response)
+
+	assert.Equal(t, response.W8BEN.Name, "Jane Doe")
+	assert.Equal(t, response.W8BEN.Country, "United Kingdom")
+	assert.Equal(t, response.W8BEN.ForeignTaxID != "", true)
+}
+
+func TestCreateFakeW8BENEJSON(t *testing.T) {
+	app := newTestApplication()
+
+	rr := serve(app.createFakeW8BENE, http.MethodGet, "/v1/tax-forms/w8bene/fake?seed=7&vendorName=Globex+Ltd&format=json", "")
+
+	assert.Equal(t, rr.Code, http.StatusOK)
+
+	var response struct {
+		W8BENE generate.W8BENEData `json:"w8bene"`
+	}
+	decodeResponse(t, rr, &response)
+
+	assert.Equal(t, response.W8BENE.Name, "Globex Ltd")
+	assert.Equal(t, response.W8BENE.Country, "United Kingdom")
+	assert.Equal(t, response.W8BENE.ForeignTaxID != "", true)
+}
+
+func TestCreateFakeTaxFormInvalidOptions(t *testing.T) {
+	app := newTestApplication()
+
+	t.Run("W-9", func(t *testing.T) {
+		testInvalidOptions(t, app.createFakeW9, http.MethodGet, "/v1/tax-forms/w9/fake", []invalidOptionsTest{
+			{"Foreign vendor", "?vendorCountry=DE", "vendorCountry"},
+			{"Annotations", "?annotations=true", "annotations"},
+		})
+	})
+
+	t.Run("W-8BEN", func(t *testing.T) {
+		testInvalidOptions(t, app.createFakeW8BEN, http.MethodGet, "/v1/tax-forms/w8ben/fake", []invalidOptionsTest{
+			{"US vendor", "?vendorCountry=US", "vendorCountry"},
+			{"Company", "?vendorName=Globex+Ltd", "vendorName"},
+			{"Random vendor", "?seed=1", "vendorName"},
+		})
+	})
+
+	t.Run("W-8BEN-E", func(t *testing.T) {
+		testInvalidOptions(t, app.createFakeW8BENE, http.MethodGet, "/v1/tax-forms/w8bene/fake", []invalidOptionsTest{
+			{"US vendor", "?vendorCountry=US", "vendorCountry"},
+		})
+	})
+}
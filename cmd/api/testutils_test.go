@@ -0,0 +1,105 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+func newTestApplication() *application {
+	return &application{
+		logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		taxEngine: tax.NewEngine(),
+	}
+}
+
+// serve runs handler on a method request for target, with body when it
+// isn't empty, and returns the recorded response.
+func serve(handler http.HandlerFunc, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rr := httptest.NewRecorder()
+
+	handler(rr, req)
+
+	return rr
+}
+
+// decodeResponse decodes the JSON body of rr into v.
+func decodeResponse(t *testing.T, rr *httptest.ResponseRecorder, v any) {
+	t.Helper()
+
+	err := json.NewDecoder(rr.Body).Decode(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// failedValidation is the body of a failedValidationResponse.
+type failedValidation struct {
+	Error  map[string]string                 `json:"error"`
+	Fields map[string][]validator.FieldError `json:"fields"`
+}
+
+// invalidOptionsTest is a query string a fake document handler rejects
+// with an error for the option key.
+type invalidOptionsTest struct {
+	name  string
+	query string
+	key   string
+}
+
+// testInvalidOptions sends the query of each test to handler at target and
+// checks the request fails validation with an error for the test key.
+func testInvalidOptions(t *testing.T, handler http.HandlerFunc, method, target string, tests []invalidOptionsTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serve(handler, method, target+tt.query, "")
+
+			assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+
+			var response failedValidation
+			decodeResponse(t, rr, &response)
+			assert.Equal(t, response.Error[tt.key] != "", true)
+		})
+	}
+}
+
+// validationTest is a document posted to a handler that fails validation
+// with errors for the fields in errors.
+type validationTest struct {
+	name   string
+	body   string
+	errors []string
+}
+
+// testValidation posts the body of each test to handler at target and
+// checks the request fails validation with a coded error for every field of
+// the test.
+func testValidation(t *testing.T, handler http.HandlerFunc, target string, tests []validationTest) {
+	t.Helper()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := serve(handler, http.MethodPost, target, tt.body)
+
+			assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+
+			var response failedValidation
+			decodeResponse(t, rr, &response)
+			for _, key := range tt.errors {
+				assert.Equal(t, response.Error[key] != "", true)
+				assert.Equal(t, len(response.Fields[key]) > 0 && response.Fields[key][0].Code != "", true)
+			}
+		})
+	}
+}
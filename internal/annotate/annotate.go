@@ -0,0 +1,254 @@
+// Package annotate recovers where each field of a document landed on the
+// rendered PDF pages.
+//
+// The layout pass runs inside Chromium: templates mark fields with a
+// data-field attribute and, when annotations are requested, include a script
+// that measures those elements and stores the measurements in the document
+// title, prefixed with TitlePrefix. Chromium copies the title into the PDF
+// metadata, which is where Extract reads it back from.
+package annotate
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const TitlePrefix = "annotations:"
+
+const (
+	cssPixelsPerInch = 96
+	pointsPerInch    = 72
+)
+
+var ErrNoAnnotations = errors.New("annotate: document has no annotations")
+
+// PageSize is the paper and margins, in inches, the PDF is printed with.
+type PageSize struct {
+	Width  float64
+	Height float64
+	Margin float64
+}
+
+// Letter matches the Gotenberg defaults: 8.5x11in with 0.39in margins.
+var Letter = PageSize{Width: 8.5, Height: 11, Margin: 0.39}
+
+// ContentWidth is the printable width in CSS pixels. Templates pin the body to
+// it while measuring so the screen layout matches the printed one.
+func (p PageSize) ContentWidth() float64 {
+	return (p.Width - 2*p.Margin) * cssPixelsPerInch
+}
+
+func (p PageSize) contentHeight() float64 {
+	return (p.Height - 2*p.Margin) * cssPixelsPerInch
+}
+
+// Box is a bounding box in PDF points, measured from the top-left corner of
+// the page.
+type Box struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+type Field struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Page  int    `json:"page"`
+	Box   Box    `json:"bbox"`
+}
+
+type Annotations struct {
+	Unit       string  `json:"unit"`
+	PageWidth  float64 `json:"page_width"`
+	PageHeight float64 `json:"page_height"`
+	Fields     []Field `json:"fields"`
+}
+
+// measurement is what the in-page script reports for each element, in CSS
+// pixels relative to the top-left corner of the document.
+type measurement struct {
+	Name   string  `json:"name"`
+	Value  string  `json:"value"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// Extract reads the measurements stored in the PDF title and converts them
+// to page numbers and boxes in points. Elements that cross a page break are
+// attributed to the page they start on.
+func Extract(pdf []byte, page PageSize) (*Annotations, error) {
+	title, err := pdfTitle(pdf)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, ok := strings.CutPrefix(title, TitlePrefix)
+	if !ok {
+		return nil, ErrNoAnnotations
+	}
+
+	var measurements []measurement
+	if err := json.Unmarshal([]byte(payload), &measurements); err != nil {
+		return nil, fmt.Errorf("annotate: invalid measurements: %v", err)
+	}
+
+	scale := float64(pointsPerInch) / cssPixelsPerInch
+	margin := page.Margin * cssPixelsPerInch
+
+	annotations := &Annotations{
+		Unit:       "pt",
+		PageWidth:  page.Width * pointsPerInch,
+		PageHeight: page.Height * pointsPerInch,
+		Fields:     make([]Field, 0, len(measurements)),
+	}
+
+	for _, m := range measurements {
+		index := math.Floor(m.Y / page.contentHeight())
+		y := m.Y - index*page.contentHeight()
+
+		annotations.Fields = append(annotations.Fields, Field{
+			Name:  m.Name,
+			Value: m.Value,
+			Page:  int(index) + 1,
+			Box: Box{
+				X:      round((margin + m.X) * scale),
+				Y:      round((margin + y) * scale),
+				Width:  round(m.Width * scale),
+				Height: round(m.Height * scale),
+			},
+		})
+	}
+
+	return annotations, nil
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// pdfTitle finds the /Title entry of the document information dictionary,
+// written either as a literal string or as a UTF-16BE hex string.
+func pdfTitle(pdf []byte) (string, error) {
+	i := bytes.Index(pdf, []byte("/Title"))
+	if i < 0 {
+		return "", ErrNoAnnotations
+	}
+
+	rest := bytes.TrimLeft(pdf[i+len("/Title"):], " \r\n\t")
+	if len(rest) == 0 {
+		return "", ErrNoAnnotations
+	}
+
+	switch rest[0] {
+	case '(':
+		return parseLiteralString(rest)
+	case '<':
+		return parseHexString(rest)
+	default:
+		return "", fmt.Errorf("annotate: unexpected /Title value")
+	}
+}
+
+func parseLiteralString(b []byte) (string, error) {
+	var out []byte
+	depth := 0
+
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == '(':
+			if depth > 0 {
+				out = append(out, c)
+			}
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return decodeTextString(out), nil
+			}
+			out = append(out, c)
+		case c == '\\' && i+1 < len(b):
+			i++
+			switch b[i] {
+			case 'n':
+				out = append(out, '\n')
+			case 'r':
+				out = append(out, '\r')
+			case 't':
+				out = append(out, '\t')
+			case 'b':
+				out = append(out, '\b')
+			case 'f':
+				out = append(out, '\f')
+			case '\r', '\n':
+				// line continuation
+			case '0', '1', '2', '3', '4', '5', '6', '7':
+				j := i
+				for j < len(b) && j < i+3 && b[j] >= '0' && b[j] <= '7' {
+					j++
+				}
+				n, _ := strconv.ParseUint(string(b[i:j]), 8, 8)
+				out = append(out, byte(n))
+				i = j - 1
+			default:
+				out = append(out, b[i])
+			}
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return "", fmt.Errorf("annotate: unterminated /Title string")
+}
+
+func parseHexString(b []byte) (string, error) {
+	end := bytes.IndexByte(b, '>')
+	if end < 0 {
+		return "", fmt.Errorf("annotate: unterminated /Title string")
+	}
+
+	digits := bytes.Map(func(r rune) rune {
+		if strings.ContainsRune(" \r\n\t", r) {
+			return -1
+		}
+		return r
+	}, b[1:end])
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		n, err := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("annotate: invalid /Title hex string")
+		}
+		out[i] = byte(n)
+	}
+
+	return decodeTextString(out), nil
+}
+
+// decodeTextString decodes a PDF text string, which is UTF-16BE when it
+// starts with a byte order mark and PDFDocEncoding (ASCII for our purposes)
+// otherwise.
+func decodeTextString(b []byte) string {
+	if len(b) >= 2 && b[0] == 0xFE && b[1] == 0xFF {
+		units := make([]uint16, 0, (len(b)-2)/2)
+		for i := 2; i+1 < len(b); i += 2 {
+			units = append(units, uint16(b[i])<<8|uint16(b[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	return string(b)
+}
@@ -0,0 +1,65 @@
+package annotate
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+const measurements = `[{"name":"invoice_number","value":"Invoice # 10001","x":400,"y":40,"width":120,"height":24},` +
+	`{"name":"items[9]","value":"Hosting (yearly)","x":10,"y":1000,"width":300,"height":48}]`
+
+func TestExtract(t *testing.T) {
+	escaped := strings.NewReplacer(`(`, `\(`, `)`, `\)`).Replace(TitlePrefix + measurements)
+
+	var hex strings.Builder
+	hex.WriteString("FEFF")
+	for _, u := range utf16.Encode([]rune(TitlePrefix + measurements)) {
+		fmt.Fprintf(&hex, "%04X", u)
+	}
+
+	tests := []struct {
+		name string
+		pdf  string
+	}{
+		{"Literal string", "%PDF-1.4\n1 0 obj\n<< /Creator (Chromium) /Title (" + escaped + ") >>\nendobj"},
+		{"Hex string", "%PDF-1.4\n1 0 obj\n<</Title <" + hex.String() + ">>>\nendobj"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations, err := Extract([]byte(tt.pdf), Letter)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, annotations.Unit, "pt")
+			assert.Equal(t, annotations.PageWidth, 612.0)
+			assert.Equal(t, annotations.PageHeight, 792.0)
+			assert.Equal(t, len(annotations.Fields), 2)
+
+			first := annotations.Fields[0]
+			assert.Equal(t, first.Name, "invoice_number")
+			assert.Equal(t, first.Page, 1)
+			assert.Equal(t, first.Box, Box{X: 328.08, Y: 58.08, Width: 90, Height: 18})
+
+			// 1000px is past the 981.12px of printable height on a Letter page
+			second := annotations.Fields[1]
+			assert.Equal(t, second.Value, "Hosting (yearly)")
+			assert.Equal(t, second.Page, 2)
+			assert.Equal(t, second.Box.Y, 42.24)
+		})
+	}
+}
+
+func TestExtractWithoutAnnotations(t *testing.T) {
+	_, err := Extract([]byte("%PDF-1.4\n<< /Title (Globex - 10001) >>"), Letter)
+	assert.Equal(t, errors.Is(err, ErrNoAnnotations), true)
+
+	_, err = Extract([]byte("%PDF-1.4\n<< /Creator (Chromium) >>"), Letter)
+	assert.Equal(t, errors.Is(err, ErrNoAnnotations), true)
+}
@@ -0,0 +1,150 @@
+package banking
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+func TestValidRoutingNumber(t *testing.T) {
+	tests := []struct {
+		routingNumber string
+		want          bool
+	}{
+		{"026001591", true},
+		{"121000248", true},
+		{"026001592", false},
+		{"02600159", false},
+		{"02600159a", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.routingNumber, func(t *testing.T) {
+			assert.Equal(t, ValidRoutingNumber(tt.routingNumber), tt.want)
+		})
+	}
+}
+
+func TestValidIBAN(t *testing.T) {
+	tests := []struct {
+		iban string
+		want bool
+	}{
+		{"DE89 3704 0044 0532 0130 00", true},
+		{"GB82WEST12345698765432", true},
+		{"gb82 west 1234 5698 7654 32", true},
+		{"DE88 3704 0044 0532 0130 00", false},
+		{"DE89 3704 0044 0532 0130 0", false},
+		{"US89 3704 0044 0532 0130 00", false},
+		{"DE89", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.iban, func(t *testing.T) {
+			assert.Equal(t, ValidIBAN(tt.iban), tt.want)
+		})
+	}
+}
+
+func TestValidBIC(t *testing.T) {
+	tests := []struct {
+		bic  string
+		want bool
+	}{
+		{"DEUTDEFF", true},
+		{"TDOMCATTTOR", true},
+		{"DEUT1EFF", false},
+		{"DEUTDEF", false},
+		{"deutdeff", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bic, func(t *testing.T) {
+			assert.Equal(t, ValidBIC(tt.bic), tt.want)
+		})
+	}
+}
+
+func TestGeneratedIdentifiersAreValid(t *testing.T) {
+	for seed := int64(0); seed < 50; seed++ {
+		fake := faker.NewWithSeed(rand.NewSource(seed))
+
+		routingNumber := RoutingNumber(fake, Bank{Country: "US"})
+		if !ValidRoutingNumber(routingNumber) {
+			t.Errorf("invalid routing number %s", routingNumber)
+		}
+
+		for _, bank := range Banks("US") {
+			routingNumber := RoutingNumber(fake, bank)
+			if !ValidRoutingNumber(routingNumber) || !strings.HasPrefix(routingNumber, bank.NationalCode) {
+				t.Errorf("invalid routing number %s for %s", routingNumber, bank.Name)
+			}
+		}
+
+		for country := range ibanFormats {
+			bank, ok := RandomBank(fake, country)
+			if !ok {
+				bank = Bank{Country: country, BIC: BIC(fake, country)}
+			}
+			if !ValidBIC(bank.BIC) {
+				t.Errorf("invalid BIC %s", bank.BIC)
+			}
+
+			iban, err := IBAN(fake, bank)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ValidIBAN(iban) {
+				t.Errorf("invalid IBAN %s", iban)
+			}
+		}
+	}
+}
+
+func TestIBANUsesBankCode(t *testing.T) {
+	fake := faker.NewWithSeed(rand.NewSource(1))
+
+	iban, err := IBAN(fake, Bank{Country: "GB", BIC: "BARCGB22"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, iban[4:8], "BARC")
+
+	_, err = IBAN(fake, Bank{Country: "US"})
+	assert.Equal(t, err != nil, true)
+}
+
+func TestRandomBank(t *testing.T) {
+	fake := faker.NewWithSeed(rand.NewSource(1))
+
+	bank, ok := RandomBank(fake, "ca")
+	assert.Equal(t, ok, true)
+	assert.Equal(t, bank.Country, "CA")
+	assert.Equal(t, len(bank.NationalCode), 3)
+
+	for _, bank := range Banks("US") {
+		assert.Equal(t, len(bank.NationalCode), 4)
+	}
+
+	_, ok = RandomBank(fake, "AQ")
+	assert.Equal(t, ok, false)
+}
+
+func TestRules(t *testing.T) {
+	account := struct {
+		IBAN          string `validate:"iban"`
+		RoutingNumber string `validate:"aba"`
+		BIC           string `validate:"bic"`
+	}{"DE88 3704 0044 0532 0130 00", "121000248", "DEUTDEFF"}
+
+	v := validator.New()
+	v.Struct(account)
+
+	assert.Equal(t, len(v.Errors), 1)
+	assert.Equal(t, v.Errors["IBAN"][0].Code, validator.CodeInvalid)
+}
@@ -0,0 +1,69 @@
+// Package banking generates and validates bank identifiers: ABA routing
+// numbers, IBANs and BICs. Generated identifiers pass the same checks real
+// payment systems run on them, and come with real bank names.
+package banking
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/jaswdr/faker/v2"
+)
+
+// Bank is a real bank. NationalCode is the domestic identifier printed next
+// to the account where the country has one: the institution number in
+// Canada, the sort code prefix in the United Kingdom, the routing number
+// prefix (Federal Reserve routing symbol) in the United States.
+type Bank struct {
+	Country      string
+	Name         string
+	BIC          string
+	NationalCode string
+}
+
+//go:embed banks.csv
+var banksCSV []byte
+
+var banksByCountry = mustLoadBanks()
+
+func mustLoadBanks() map[string][]Bank {
+	records, err := csv.NewReader(bytes.NewReader(banksCSV)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("banking: invalid banks.csv: %v", err))
+	}
+
+	index := make(map[string][]Bank)
+	for _, record := range records[1:] {
+		bank := Bank{
+			Country:      record[0],
+			Name:         record[1],
+			BIC:          record[2],
+			NationalCode: record[3],
+		}
+		if !ValidBIC(bank.BIC) {
+			panic(fmt.Sprintf("banking: invalid BIC for %s in banks.csv: %s", bank.Name, bank.BIC))
+		}
+
+		index[bank.Country] = append(index[bank.Country], bank)
+	}
+
+	return index
+}
+
+// Banks returns the known banks of country, an ISO 3166-1 alpha-2 code.
+func Banks(country string) []Bank {
+	return append([]Bank(nil), banksByCountry[strings.ToUpper(country)]...)
+}
+
+// RandomBank picks one of the known banks of country.
+func RandomBank(fake faker.Faker, country string) (Bank, bool) {
+	banks := banksByCountry[strings.ToUpper(country)]
+	if len(banks) == 0 {
+		return Bank{}, false
+	}
+
+	return banks[fake.IntBetween(0, len(banks)-1)], true
+}
@@ -0,0 +1,204 @@
+package banking
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jaswdr/faker/v2"
+)
+
+var abaWeights = [9]int{3, 7, 1, 3, 7, 1, 3, 7, 1}
+
+// RoutingNumber generates an ABA routing number of bank with a valid check
+// digit. It starts with the routing prefix of the bank, its NationalCode, or
+// for banks without one with one of the twelve Federal Reserve district
+// prefixes (01-12), like the routing numbers of ordinary banks.
+func RoutingNumber(fake faker.Faker, bank Bank) string {
+	prefix := bank.NationalCode
+	if prefix == "" {
+		prefix = fmt.Sprintf("%02d", fake.IntBetween(1, 12))
+	}
+	digits := prefix + fake.Numerify(strings.Repeat("#", 8-len(prefix)))
+
+	sum := 0
+	for i, c := range digits {
+		sum += int(c-'0') * abaWeights[i]
+	}
+
+	return digits + strconv.Itoa((10-sum%10)%10)
+}
+
+// ValidRoutingNumber reports whether s is nine digits passing the ABA
+// checksum.
+func ValidRoutingNumber(s string) bool {
+	if len(s) != 9 {
+		return false
+	}
+
+	sum := 0
+	for i, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+		sum += int(c-'0') * abaWeights[i]
+	}
+
+	return sum%10 == 0
+}
+
+// ibanFormats holds the BBAN structure of each IBAN country, following the
+// SWIFT IBAN registry notation: n digits, a upper case letters and c
+// alphanumerics, each preceded by its length.
+var ibanFormats = map[string]string{
+	"AT": "5n11n",
+	"BE": "3n7n2n",
+	"BG": "4a4n2n8c",
+	"BR": "8n5n10n1a1c",
+	"CH": "5n12c",
+	"CY": "3n5n16c",
+	"CZ": "4n6n10n",
+	"DE": "8n10n",
+	"DK": "4n9n1n",
+	"EE": "2n2n11n1n",
+	"ES": "4n4n1n1n10n",
+	"FI": "3n11n",
+	"FR": "5n5n11c2n",
+	"GB": "4a6n8n",
+	"GR": "3n4n16c",
+	"HR": "7n10n",
+	"HU": "3n4n1n15n1n",
+	"IE": "4a6n8n",
+	"IS": "4n2n6n10n",
+	"IT": "1a5n5n12c",
+	"LI": "5n12c",
+	"LT": "5n11n",
+	"LU": "3n13c",
+	"LV": "4a13c",
+	"MC": "5n5n11c2n",
+	"MT": "4a5n18c",
+	"NL": "4a10n",
+	"NO": "4n6n1n",
+	"PL": "8n16n",
+	"PT": "4n4n11n2n",
+	"RO": "4a16c",
+	"SE": "3n16n1n",
+	"SI": "5n8n2n",
+	"SK": "4n6n10n",
+	"SM": "1a5n5n12c",
+}
+
+var ibanFormatRX = regexp.MustCompile(`(\d+)([nac])`)
+
+// HasIBAN reports whether accounts in country are identified by IBAN.
+func HasIBAN(country string) bool {
+	_, ok := ibanFormats[strings.ToUpper(country)]
+	return ok
+}
+
+// IBAN generates an IBAN for an account at bank, with the country-specific
+// BBAN length and correct mod-97 check digits. In countries whose BBAN
+// starts with a four letter bank code (GB, IE, NL...) the code is taken from
+// the bank's BIC. National check digits inside the BBAN are not computed.
+func IBAN(fake faker.Faker, bank Bank) (string, error) {
+	country := strings.ToUpper(bank.Country)
+	format, ok := ibanFormats[country]
+	if !ok {
+		return "", fmt.Errorf("banking: no IBAN format for country %q", bank.Country)
+	}
+
+	var bban strings.Builder
+	for i, part := range ibanFormatRX.FindAllStringSubmatch(format, -1) {
+		n, _ := strconv.Atoi(part[1])
+
+		if i == 0 && part[2] == "a" && n == 4 && len(bank.BIC) >= 4 {
+			bban.WriteString(bank.BIC[:4])
+			continue
+		}
+
+		for j := 0; j < n; j++ {
+			switch part[2] {
+			case "n":
+				bban.WriteString(strconv.Itoa(fake.RandomDigit()))
+			case "a":
+				bban.WriteByte(byte('A' + fake.IntBetween(0, 25)))
+			case "c":
+				bban.WriteByte("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"[fake.IntBetween(0, 35)])
+			}
+		}
+	}
+
+	check := 98 - mod97(bban.String()+country+"00")
+
+	return fmt.Sprintf("%s%02d%s", country, check, bban.String()), nil
+}
+
+// ValidIBAN reports whether s, with or without the spaces of the printed
+// form, has the length of its country and passes the mod-97 check.
+func ValidIBAN(s string) bool {
+	iban := strings.ToUpper(strings.ReplaceAll(s, " ", ""))
+	if len(iban) < 5 {
+		return false
+	}
+
+	format, ok := ibanFormats[iban[:2]]
+	if !ok {
+		return false
+	}
+
+	length := 4
+	for _, part := range ibanFormatRX.FindAllStringSubmatch(format, -1) {
+		n, _ := strconv.Atoi(part[1])
+		length += n
+	}
+	if len(iban) != length {
+		return false
+	}
+
+	for _, c := range iban {
+		if !(c >= '0' && c <= '9' || c >= 'A' && c <= 'Z') {
+			return false
+		}
+	}
+
+	return mod97(iban[4:]+iban[:4]) == 1
+}
+
+// mod97 computes s mod 97, with letters standing for the numbers 10 to 35,
+// one digit at a time so that arbitrarily long strings fit.
+func mod97(s string) int {
+	r := 0
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			r = (r*10 + int(c-'0')) % 97
+		default:
+			r = (r*100 + int(c-'A') + 10) % 97
+		}
+	}
+	return r
+}
+
+// FormatIBAN prints an IBAN in groups of four characters, as on paper.
+func FormatIBAN(iban string) string {
+	var groups []string
+	for i := 0; i < len(iban); i += 4 {
+		groups = append(groups, iban[i:min(i+4, len(iban))])
+	}
+	return strings.Join(groups, " ")
+}
+
+var bicRX = regexp.MustCompile(`^[A-Z]{4}[A-Z]{2}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
+
+// ValidBIC reports whether s is a well-formed BIC: bank code, country,
+// location and an optional branch code.
+func ValidBIC(s string) bool {
+	return bicRX.MatchString(s)
+}
+
+// BIC generates a well-formed eight character BIC in country, for banks
+// that aren't in the list.
+func BIC(fake faker.Faker, country string) string {
+	return strings.ToUpper(fake.Lexify("????")+country) + strings.ToUpper(fake.Bothify("?#"))
+}
@@ -0,0 +1,10 @@
+package banking
+
+import "tools.lucasfaria.dev/internal/validator"
+
+// The identifiers of this package can be checked in validate tags.
+func init() {
+	validator.RegisterRule("iban", validator.Rule{Check: ValidIBAN, Code: validator.CodeInvalid, Message: "must be a valid IBAN"})
+	validator.RegisterRule("aba", validator.Rule{Check: ValidRoutingNumber, Code: validator.CodeInvalid, Message: "must be a valid ABA routing number"})
+	validator.RegisterRule("bic", validator.Rule{Check: ValidBIC, Code: validator.CodeInvalid, Message: "must be a valid BIC"})
+}
@@ -11,7 +11,44 @@ import (
 
 const gotenbergURL = "http://gotenberg:3000"
 
-func HtmlToPdfV2(htmlFile *os.File) ([]byte, error) {
+// RequestOption adjusts the Gotenberg request a page is converted with, such
+// as its paper size.
+type RequestOption func(req *gotenberg.HTMLRequest)
+
+func HtmlToPdfV2(htmlFile *os.File, options ...RequestOption) ([]byte, error) {
+	return convertHtml(htmlFile, false, options)
+}
+
+// HtmlToPng takes a screenshot of the rendered page through Gotenberg's
+// Chromium screenshot route.
+//
+// The client's Format setter doesn't set the format form field, so the
+// screenshot always comes back as Gotenberg's default PNG; other formats are
+// encoded from it on our side.
+func HtmlToPng(htmlFile *os.File, options ...RequestOption) ([]byte, error) {
+	return convertHtml(htmlFile, true, options)
+}
+
+// ReceiptPaper is the 80mm thermal roll receipts are printed on. The height
+// only matters until the request asks for a single page, which Gotenberg
+// stretches to the length of the content.
+var ReceiptPaper = gotenberg.PaperDimensions{
+	Width:  80,
+	Height: 297,
+	Unit:   gotenberg.MM,
+}
+
+// ReceiptPage converts a receipt to a single, margin-less page of
+// ReceiptPaper, the way it would come out of the printer.
+func ReceiptPage(req *gotenberg.HTMLRequest) {
+	req.PaperSize(ReceiptPaper)
+	req.Margins(gotenberg.NoMargins)
+	req.SinglePage()
+}
+
+// convertHtml sends htmlFile to Gotenberg's Chromium routes, the screenshot
+// one or the PDF one, and returns the converted content.
+func convertHtml(htmlFile *os.File, screenshot bool, options []RequestOption) ([]byte, error) {
 	client := &gotenberg.Client{
 		Hostname: gotenbergURL,
 	}
@@ -23,20 +60,30 @@ func HtmlToPdfV2(htmlFile *os.File) ([]byte, error) {
 
 	req := gotenberg.NewHTMLRequest(index)
 	req.SkipNetworkIdleEvent()
+	for _, option := range options {
+		option(req)
+	}
 
-	client.Store(req, "/gotenberg/test.pdf")
+	var resp *http.Response
+	if screenshot {
+		resp, err = client.Screenshot(req)
+	} else {
+		resp, err = client.Post(req)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach gotenberg: %v", err)
+	}
+	defer resp.Body.Close()
 
-	resp, _ := client.Post(req)
 	if resp.StatusCode != http.StatusOK {
 		bodyBytes, _ := io.ReadAll(resp.Body)
 		return nil, fmt.Errorf("gotenberg responded with status code %d: %s", resp.StatusCode, string(bodyBytes))
 	}
-	defer resp.Body.Close()
 
-	pdfContent, err := io.ReadAll(resp.Body)
+	content, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	return pdfContent, nil
+	return content, nil
 }
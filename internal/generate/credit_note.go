@@ -0,0 +1,173 @@
+package generate
+
+import (
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+// CreditReasons maps the reasons a fake credit note can be issued for to the
+// text printed on it. Returns and damages credit part of the quantities,
+// pricing errors part of a unit price, duplicates and cancellations the whole
+// invoice.
+var CreditReasons = map[string]string{
+	"returned":  "Returned goods",
+	"damaged":   "Goods damaged in transit",
+	"pricing":   "Pricing error",
+	"duplicate": "Duplicate billing",
+	"cancelled": "Order cancelled",
+}
+
+// CreditReasonNames lists the keys of CreditReasons in a stable order.
+var CreditReasonNames = []string{"returned", "damaged", "pricing", "duplicate", "cancelled"}
+
+// CreditNoteData reverses all or part of an invoice. The credited items are
+// given like invoice items, with positive quantities and prices; the amounts
+// CalculateTotals derives from them are negative, down to the Total.
+type CreditNoteData struct {
+	CompanyLogo           string
+	CreditNoteNumber      string `validate:"required,max=64"`
+	CreditNoteDate        string `validate:"required,date"`
+	OriginalInvoiceNumber string `validate:"required,max=64"`
+	OriginalInvoiceDate   string `validate:"required,date"`
+	Reason                string `validate:"required,max=500"`
+	VendorInfo            CompanyInfo
+	CustomerInfo          CompanyInfo
+	Currency              string        `validate:"required,currency"`
+	Items                 []InvoiceItem `validate:"required,dive"`
+	Subtotal              int64
+	DiscountTotal         int64
+	Taxes                 []tax.Line
+	TaxNotes              []string
+	TaxTotal              int64
+	Total                 int64
+}
+
+const CreditNoteTemplate = "credit_note.tmpl"
+
+// GenerateRandomCreditNoteData draws the invoice of the options, exactly as
+// GenerateRandomInvoiceData does, and a credit note correcting it for reason,
+// one of CreditReasons or a random one when empty. It fails when the invoice
+// does.
+func GenerateRandomCreditNoteData(options *GenerateInvoiceOptions, reason string) (CreditNoteData, InvoiceData, error) {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+	invoice, err := generateInvoiceData(fake, options)
+	if err != nil {
+		return CreditNoteData{}, InvoiceData{}, err
+	}
+
+	if reason == "" {
+		reason = fake.RandomStringElement(CreditReasonNames)
+	}
+
+	note := CreditNoteData{
+		CompanyLogo:           invoice.CompanyLogo,
+		CreditNoteNumber:      "CN-" + fake.Numerify("#####"),
+		CreditNoteDate:        addDays(invoice.InvoiceDate, fake.IntBetween(1, 20)),
+		OriginalInvoiceNumber: invoice.InvoiceNumber,
+		OriginalInvoiceDate:   invoice.InvoiceDate,
+		Reason:                CreditReasons[reason],
+		VendorInfo:            invoice.VendorInfo,
+		CustomerInfo:          invoice.CustomerInfo,
+		Currency:              invoice.Currency,
+		Items:                 creditedItems(fake, reason, invoice.Items),
+		Taxes:                 slices.Clone(invoice.Taxes),
+		TaxNotes:              invoice.TaxNotes,
+	}
+	note.CalculateTotals()
+
+	return note, invoice, nil
+}
+
+// creditedItems picks the invoice lines a credit note for reason gives back.
+func creditedItems(fake faker.Faker, reason string, items []InvoiceItem) []InvoiceItem {
+	credited := []InvoiceItem{}
+
+	switch reason {
+	case "returned", "damaged":
+		// part of the quantity of one to all lines, always at least one
+		first := fake.IntBetween(0, len(items)-1)
+		for i, item := range items {
+			if i != first && fake.Boolean().Bool() {
+				continue
+			}
+
+			quantity := fake.Int64Between(1, max(item.Quantity, 1))
+			if item.Discount.Type == DiscountFixed && item.Quantity > 0 {
+				item.Discount.Amount = item.Discount.Amount * quantity / item.Quantity
+			}
+			item.Quantity = quantity
+			credited = append(credited, item)
+		}
+
+	case "pricing":
+		// the difference to the agreed price on one line
+		item := items[fake.IntBetween(0, len(items)-1)]
+		item.UnitPrice = max(money.Rate(fake.IntBetween(5, 30)*1000).Of(item.UnitPrice), 1)
+		item.Discount = Discount{}
+		credited = append(credited, item)
+
+	default:
+		credited = append(credited, items...)
+	}
+
+	return credited
+}
+
+// CalculateTotals derives the item amounts and totals like
+// InvoiceData.CalculateTotals, then turns them negative.
+func (d *CreditNoteData) CalculateTotals() {
+	d.Subtotal, d.DiscountTotal, d.TaxTotal = calculateTotals(d.Items, d.Taxes)
+	d.Total = d.Subtotal - d.DiscountTotal + d.TaxTotal
+
+	for i := range d.Items {
+		d.Items[i].DiscountAmount = -d.Items[i].DiscountAmount
+		d.Items[i].TaxAmount = -d.Items[i].TaxAmount
+		d.Items[i].LineTotal = -d.Items[i].LineTotal
+	}
+	for i := range d.Taxes {
+		d.Taxes[i].Amount = -d.Taxes[i].Amount
+	}
+	d.Subtotal, d.DiscountTotal, d.TaxTotal, d.Total = -d.Subtotal, -d.DiscountTotal, -d.TaxTotal, -d.Total
+}
+
+// ApplyTax replaces the credit note taxes with the ones the engine computes
+// for the vendor and customer jurisdictions, see InvoiceData.ApplyTax.
+func (d *CreditNoteData) ApplyTax(engine *tax.Engine) error {
+	return applyTax(d, engine)
+}
+
+func (d *CreditNoteData) taxFields() (vendor, customer tax.Jurisdiction, lines *[]tax.Line, notes *[]string) {
+	return d.VendorInfo.Jurisdiction(), d.CustomerInfo.Jurisdiction(), &d.Taxes, &d.TaxNotes
+}
+
+func (d *CreditNoteData) totals() totals {
+	return totals{d.Items, d.Subtotal, d.DiscountTotal, d.TaxTotal, d.Total}
+}
+
+// ValidateCreditNote checks the credit note data sent by a client before it
+// is rendered, like ValidateInvoice.
+func ValidateCreditNote(v *validator.Validator, d *CreditNoteData) {
+	v.Struct(d)
+
+	creditNoteDate, creditNoteDateErr := time.Parse(time.DateOnly, d.CreditNoteDate)
+	invoiceDate, invoiceDateErr := time.Parse(time.DateOnly, d.OriginalInvoiceDate)
+	if creditNoteDateErr == nil && invoiceDateErr == nil {
+		v.CheckCode(!creditNoteDate.Before(invoiceDate), "CreditNoteDate", validator.CodeOutOfRange, "must not be before OriginalInvoiceDate")
+	}
+
+	validateItems(v, d.Items)
+	validateTaxes(v, d.Taxes)
+}
+
+// ValidateCreditNoteTotals checks the amounts a client sent against the ones
+// derived from its items, like ValidateTotals. The amounts sent must already
+// be negative.
+func ValidateCreditNoteTotals(v *validator.Validator, sent, calculated *CreditNoteData) {
+	validateTotals(v, sent.totals(), calculated.totals())
+}
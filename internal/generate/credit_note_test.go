@@ -0,0 +1,87 @@
+package generate
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+func TestGenerateRandomCreditNoteData(t *testing.T) {
+	options := &GenerateInvoiceOptions{
+		Seed:          5,
+		NumberOfItems: 4,
+		InvoiceDate:   "2024-03-05",
+		DueDate:       "2024-04-04",
+		Currency:      "usd",
+		TaxEngine:     tax.NewEngine(),
+	}
+
+	for _, reason := range CreditReasonNames {
+		t.Run(reason, func(t *testing.T) {
+			note, invoice, err := GenerateRandomCreditNoteData(options, reason)
+			if err != nil {
+				t.Fatal(err)
+			}
+			original, err := GenerateRandomInvoiceData(options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, fmt.Sprintf("%+v", invoice), fmt.Sprintf("%+v", original))
+			assert.Equal(t, note.OriginalInvoiceNumber, invoice.InvoiceNumber)
+			assert.Equal(t, note.OriginalInvoiceDate, invoice.InvoiceDate)
+			assert.Equal(t, note.CreditNoteDate > invoice.InvoiceDate, true)
+			assert.Equal(t, note.Reason, CreditReasons[reason])
+			assert.Equal(t, note.Total < 0, true)
+			assert.Equal(t, -note.Total <= invoice.Total, true)
+
+			// every credited line is one of the invoice, with at most its
+			// quantity and price
+			for _, item := range note.Items {
+				assert.Equal(t, item.LineTotal < 0, true)
+				i := slices.IndexFunc(invoice.Items, func(invoiced InvoiceItem) bool {
+					return invoiced.Description == item.Description
+				})
+				assert.Equal(t, i >= 0, true)
+				assert.Equal(t, item.Quantity <= invoice.Items[i].Quantity, true)
+				assert.Equal(t, item.UnitPrice <= invoice.Items[i].UnitPrice, true)
+			}
+
+			// the note credits what billing the credited items would cost
+			credited := InvoiceData{Currency: note.Currency, Items: slices.Clone(note.Items), Taxes: slices.Clone(invoice.Taxes)}
+			credited.CalculateTotals()
+			assert.Equal(t, note.Total, -credited.Total)
+			assert.Equal(t, note.TaxTotal, -credited.TaxTotal)
+
+			switch reason {
+			case "duplicate", "cancelled":
+				assert.Equal(t, note.Total, -invoice.Total)
+			case "pricing":
+				assert.Equal(t, len(note.Items), 1)
+			}
+		})
+	}
+}
+
+func TestValidateCreditNoteTotals(t *testing.T) {
+	sent := CreditNoteData{
+		Currency: "USD",
+		Items:    []InvoiceItem{{Description: "Widgets", Quantity: 2, UnitPrice: 500, TaxRate: 10000}},
+		Total:    -1100,
+	}
+	calculated := sent
+	calculated.Items = slices.Clone(sent.Items)
+	calculated.CalculateTotals()
+
+	v := validator.New()
+	ValidateCreditNoteTotals(v, &sent, &calculated)
+	assert.Equal(t, v.Valid(), true)
+
+	sent.Total = 1100
+	ValidateCreditNoteTotals(v, &sent, &calculated)
+	assert.Equal(t, v.Errors["Total"][0], validator.FieldError{Code: validator.CodeMismatch, Message: "must match the items (-1100)"})
+}
@@ -0,0 +1,198 @@
+package generate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jaswdr/faker/v2"
+)
+
+// foreignTINs draw the number an individual resident of the country is
+// identified by for tax purposes, with its check digits, as written on line
+// 6a of a W-8BEN. Residents of other countries get nine random digits.
+var foreignTINs = map[string]func(fake faker.Faker) string{
+	"AU": fakeTFN,
+	"CA": fakeSIN,
+	"DE": fakeSteuerID,
+	"ES": fakeDNI,
+	"FR": fakeNumeroFiscal,
+	"GB": fakeUTR,
+	"NL": fakeBSN,
+}
+
+// fakeForeignTIN draws the tax identification number of an individual
+// resident of country, see foreignTINs.
+func fakeForeignTIN(fake faker.Faker, country string) string {
+	if tin, ok := foreignTINs[country]; ok {
+		return tin(fake)
+	}
+	return fake.Numerify("#########")
+}
+
+// fakeDigits draws n digits.
+func fakeDigits(fake faker.Faker, n int) []int {
+	digits := make([]int, n)
+	for i := range digits {
+		digits[i] = fake.IntBetween(0, 9)
+	}
+	return digits
+}
+
+// fakeShuffle permutes digits in place.
+func fakeShuffle(fake faker.Faker, digits []int) {
+	for i := len(digits) - 1; i > 0; i-- {
+		j := fake.IntBetween(0, i)
+		digits[i], digits[j] = digits[j], digits[i]
+	}
+}
+
+func joinDigits(digits []int) string {
+	var b strings.Builder
+	for _, d := range digits {
+		b.WriteByte(byte('0' + d))
+	}
+	return b.String()
+}
+
+// fakeSteuerID draws a German Steuerliche Identifikationsnummer: ten digits,
+// the first not zero, in which exactly one digit occurs twice, followed by an
+// ISO 7064 MOD 11,10 check digit.
+func fakeSteuerID(fake faker.Faker) string {
+	digits := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	fakeShuffle(fake, digits)
+	// nine distinct digits and a second one of them
+	digits[9] = digits[fake.IntBetween(0, 8)]
+	fakeShuffle(fake, digits)
+	if digits[0] == 0 {
+		k := 1
+		for digits[k] == 0 {
+			k++
+		}
+		digits[0], digits[k] = digits[k], digits[0]
+	}
+
+	return joinDigits(append(digits, iso7064Mod1110(digits)))
+}
+
+// iso7064Mod1110 computes the ISO 7064 MOD 11,10 check digit of digits.
+func iso7064Mod1110(digits []int) int {
+	product := 10
+	for _, d := range digits {
+		sum := (d + product) % 10
+		if sum == 0 {
+			sum = 10
+		}
+		product = sum * 2 % 11
+	}
+
+	check := 11 - product
+	if check == 10 {
+		check = 0
+	}
+	return check
+}
+
+// utrWeights weigh the last nine digits of a UK Unique Taxpayer Reference.
+var utrWeights = []int{6, 7, 8, 9, 10, 5, 4, 3, 2}
+
+// fakeUTR draws a UK Unique Taxpayer Reference: a check digit followed by
+// nine digits weighted by utrWeights.
+func fakeUTR(fake faker.Faker) string {
+	digits := fakeDigits(fake, 9)
+	return strconv.Itoa(utrCheckDigit(digits)) + joinDigits(digits)
+}
+
+// utrCheckDigit is 11 minus the weighted sum modulo 11, with 10 and 11
+// written 1 and 2.
+func utrCheckDigit(digits []int) int {
+	sum := 0
+	for i, d := range digits {
+		sum += d * utrWeights[i]
+	}
+
+	check := 11 - sum%11
+	if check > 9 {
+		check -= 9
+	}
+	return check
+}
+
+// fakeNumeroFiscal draws a French numéro fiscal de référence: thirteen
+// digits, the first one 0 to 3, the last three the first ten modulo 511.
+func fakeNumeroFiscal(fake faker.Faker) string {
+	digits := append([]int{fake.IntBetween(0, 3)}, fakeDigits(fake, 9)...)
+
+	number := joinDigits(digits)
+	n, _ := strconv.ParseInt(number, 10, 64)
+	return fmt.Sprintf("%s%03d", number, n%511)
+}
+
+const dniLetters = "TRWAGMYFPDXBNJZSQVHLCKE"
+
+// fakeDNI draws a Spanish NIF of a citizen: the eight digits of the DNI and
+// the letter of their remainder modulo 23.
+func fakeDNI(fake faker.Faker) string {
+	n := fake.IntBetween(1000000, 99999999)
+	return fmt.Sprintf("%08d%c", n, dniLetters[n%23])
+}
+
+// fakeBSN draws a Dutch burgerservicenummer, nine digits passing the
+// eleven test: weighted 9 down to 2, minus the last, divisible by 11.
+func fakeBSN(fake faker.Faker) string {
+	for {
+		digits := append([]int{fake.IntBetween(1, 9)}, fakeDigits(fake, 7)...)
+		sum := 0
+		for i, d := range digits {
+			sum += (9 - i) * d
+		}
+		// no digit completes remainders of 10
+		if check := sum % 11; check < 10 {
+			return joinDigits(append(digits, check))
+		}
+	}
+}
+
+// fakeSIN draws a Canadian Social Insurance Number, printed 123 456 789: a
+// first digit of 1 to 7, for the province of registration, and a Luhn check
+// digit.
+func fakeSIN(fake faker.Faker) string {
+	digits := append([]int{fake.IntBetween(1, 7)}, fakeDigits(fake, 7)...)
+	s := joinDigits(append(digits, luhnCheckDigit(digits)))
+	return s[:3] + " " + s[3:6] + " " + s[6:]
+}
+
+// luhnCheckDigit computes the digit that makes digits pass the Luhn check.
+func luhnCheckDigit(digits []int) int {
+	sum := 0
+	for i := range digits {
+		d := digits[len(digits)-1-i]
+		if i%2 == 0 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// tfnWeights weigh the nine digits of an Australian Tax File Number.
+var tfnWeights = []int{1, 4, 3, 7, 5, 8, 6, 9, 10}
+
+// fakeTFN draws an Australian Tax File Number, printed 123 456 782, whose
+// digits weighted by tfnWeights add up to a multiple of 11.
+func fakeTFN(fake faker.Faker) string {
+	for {
+		digits := append([]int{fake.IntBetween(1, 9)}, fakeDigits(fake, 7)...)
+		sum := 0
+		for i, d := range digits {
+			sum += d * tfnWeights[i]
+		}
+		// the last digit weighs 10, that is -1 modulo 11
+		if check := sum % 11; check < 10 {
+			s := joinDigits(append(digits, check))
+			return s[:3] + " " + s[3:6] + " " + s[6:]
+		}
+	}
+}
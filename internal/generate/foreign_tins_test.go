@@ -0,0 +1,114 @@
+package generate
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+// validForeignTIN tells if tin is a valid tax identification number of an
+// individual resident of country, one of foreignTINs.
+func validForeignTIN(country, tin string) bool {
+	tin = strings.ReplaceAll(tin, " ", "")
+	lengths := map[string]int{"AU": 9, "CA": 9, "DE": 11, "ES": 9, "FR": 13, "GB": 10, "NL": 9}
+	if len(tin) != lengths[country] {
+		return false
+	}
+
+	digits := make([]int, 0, len(tin))
+	for i, r := range tin {
+		if country == "ES" && i == 8 {
+			break
+		}
+		if r < '0' || r > '9' {
+			return false
+		}
+		digits = append(digits, int(r-'0'))
+	}
+
+	weigh := func(digits, weights []int) (sum int) {
+		for i, d := range digits {
+			sum += d * weights[i]
+		}
+		return sum
+	}
+
+	switch country {
+	case "AU":
+		return weigh(digits, []int{1, 4, 3, 7, 5, 8, 6, 9, 10})%11 == 0
+	case "CA":
+		sum := 0
+		for i, d := range digits {
+			if i%2 == 1 {
+				if d *= 2; d > 9 {
+					d -= 9
+				}
+			}
+			sum += d
+		}
+		return sum%10 == 0
+	case "DE":
+		counts := map[int]int{}
+		for _, d := range digits[:10] {
+			counts[d]++
+		}
+		return digits[0] != 0 && len(counts) == 9 && iso7064Mod1110(digits[:10]) == digits[10]
+	case "ES":
+		n, _ := strconv.Atoi(tin[:8])
+		return tin[8] == "TRWAGMYFPDXBNJZSQVHLCKE"[n%23]
+	case "FR":
+		n, _ := strconv.ParseInt(tin[:10], 10, 64)
+		key, _ := strconv.ParseInt(tin[10:], 10, 64)
+		return digits[0] <= 3 && n%511 == key
+	case "GB":
+		return "21987654321"[weigh(digits[1:], []int{6, 7, 8, 9, 10, 5, 4, 3, 2})%11] == tin[0]
+	case "NL":
+		return (weigh(digits[:8], []int{9, 8, 7, 6, 5, 4, 3, 2})-digits[8])%11 == 0
+	}
+	return false
+}
+
+func TestFakeForeignTIN(t *testing.T) {
+	fake := faker.NewWithSeed(rand.NewSource(1))
+	for country := range foreignTINs {
+		for i := 0; i < 100; i++ {
+			tin := fakeForeignTIN(fake, country)
+			if !validForeignTIN(country, tin) {
+				t.Errorf("%s: invalid TIN %q", country, tin)
+			}
+		}
+	}
+	assert.Equal(t, len(fakeForeignTIN(fake, "JP")), 9)
+
+	tests := []struct {
+		country string
+		tin     string
+		valid   bool
+	}{
+		{"AU", "123 456 782", true},
+		{"AU", "123 456 789", false},
+		{"CA", "046 454 286", true},
+		{"CA", "046 454 287", false},
+		{"DE", "86095742719", true},
+		{"DE", "86095742718", false},
+		{"DE", "12345678903", false},
+		{"ES", "12345678Z", true},
+		{"ES", "12345678A", false},
+		{"FR", "1234567890066", true},
+		{"FR", "1234567890067", false},
+		{"GB", "1123456789", true},
+		{"GB", "2123456789", false},
+		{"NL", "111222333", true},
+		{"NL", "111222334", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.tin, func(t *testing.T) {
+			assert.Equal(t, validForeignTIN(tt.country, tt.tin), tt.valid)
+		})
+	}
+}
@@ -1,35 +1,68 @@
 package generate
 
 import (
+	"errors"
 	"fmt"
 	"html"
 	"html/template"
+	"math/rand"
 	"os"
-	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/jaswdr/faker/v2"
-	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
-	"tools.lucasfaria.dev/internal/utils"
+	"tools.lucasfaria.dev/internal/annotate"
+	"tools.lucasfaria.dev/internal/i18n"
+	"tools.lucasfaria.dev/internal/logo"
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/tax"
 )
 
+// GenerateInvoiceOptions Seed drives every random choice of the generator, so
+// the same options always produce the same invoice. A zero AccountNumber or
+// NumberOfItems is drawn from the seed as well, and without PaymentMethods the
+// vendor gets the usual rail of its country. The json names are the query
+// parameters of the fake invoice endpoints.
 type GenerateInvoiceOptions struct {
-	PaymentMethods []string
-	VendorName     string
-	AccountNumber  int64
-	NumberOfItems  int
-	InvoiceDate    string
-	DueDate        string
-	Currency       string
+	Seed           int64            `json:"seed"`
+	PaymentMethods []string         `json:"paymentMethods"`
+	VendorName     string           `json:"vendorName" validate:"max=200"`
+	AccountNumber  int64            `json:"accountNumber" validate:"min=0"`
+	NumberOfItems  int              `json:"numberOfItems" validate:"min=1,max=20"`
+	InvoiceDate    string           `json:"createdAt" validate:"date"`
+	DueDate        string           `json:"dueAt" validate:"date"`
+	Currency       string           `json:"currency" validate:"currency"`
+	VendorCountry  string           `json:"vendorCountry" validate:"len=2"`
+	VendorRegion   string           `json:"vendorRegion" validate:"max=3"`
+	Customer       tax.Jurisdiction `json:"-"`
+	TaxEngine      *tax.Engine      `json:"-"`
+
+	// the bill-to party is Acme Corp., or a company drawn from the seed with
+	// RandomCustomer; the fields given here override either
+	RandomCustomer  bool   `json:"randomCustomer"`
+	CustomerName    string `json:"customerName" validate:"max=200"`
+	CustomerAddress string `json:"customerAddress" validate:"max=200"`
+	CustomerCity    string `json:"customerCity" validate:"max=200"`
+	CustomerEmail   string `json:"customerEmail" validate:"email"`
+
+	// ShipTo and RemitTo add the optional delivery and payment address blocks
+	ShipTo  bool `json:"shipTo"`
+	RemitTo bool `json:"remitTo"`
 }
 
+// CompanyInfo Country is an ISO 3166-1 alpha-2 code and Region a state or
+// province code within it; together with TaxID they place the company in a
+// tax jurisdiction.
 type CompanyInfo struct {
-	Name          string
-	StreetAddress string
-	CityStateZip  string
-	Email         string
+	Name          string `validate:"required,max=200"`
+	StreetAddress string `validate:"max=200"`
+	CityStateZip  string `validate:"max=200"`
+	Email         string `validate:"email"`
+	Country       string `validate:"len=2"`
+	Region        string `validate:"max=3"`
+	TaxID         string `validate:"max=32"`
 }
 type InvoicePaymentDetails struct {
 	Name  string
@@ -41,199 +74,182 @@ type PaymentMethod struct {
 	Details []InvoicePaymentDetails
 }
 
+// InvoiceData validate tags hold the rules for invoices sent by clients, see
+// ValidateInvoice.
 type InvoiceData struct {
 	CompanyLogo    string
-	InvoiceNumber  string
-	InvoiceDate    string
-	DueDate        string
+	InvoiceNumber  string `validate:"required,max=64"`
+	PONumber       string `json:",omitempty" validate:"max=64"`
+	InvoiceDate    string `validate:"required,date"`
+	DueDate        string `validate:"required,date"`
 	VendorInfo     CompanyInfo
 	CustomerInfo   CompanyInfo
+	ShipTo         *CompanyInfo `json:",omitempty"`
+	RemitTo        *CompanyInfo `json:",omitempty"`
 	PaymentMethods []PaymentMethod
 	PaymentMethod  string
 	PaymentDetails []InvoicePaymentDetails
-	Items          []InvoiceItem
-	Total          string
+	Currency       string        `validate:"required,currency"`
+	Items          []InvoiceItem `validate:"required,dive"`
+	Subtotal       int64
+	DiscountTotal  int64
+	Taxes          []tax.Line
+	TaxNotes       []string
+	TaxTotal       int64
+	Total          int64
 }
 
-type InvoiceItem struct {
-	Description string
-	Price       string
-}
+const InvoiceTemplate = "invoice.tmpl"
 
-const tmplFile = "invoice.tmpl"
-
-func getCurrenctSymbol(currency string) string {
-	switch currency {
-	case "usd":
-		return "$"
-	case "eur":
-		return "€"
-	case "jpy":
-		return "¥"
-	case "gbp":
-		return "£"
-	case "aud":
-		return "A$"
-	case "cad":
-		return "C$"
-	case "chf":
-		return "CHF"
-	case "cny":
-		return "¥"
-	case "sek":
-		return "kr"
-	case "nzd":
-		return "NZ$"
-	case "mxn":
-		return "Mex$"
-	case "sgd":
-		return "S$"
-	case "hkd":
-		return "HK$"
-	case "nok":
-		return "kr"
-	case "krw":
-		return "₩"
-	case "try":
-		return "₺"
-	case "rub":
-		return "₽"
-	case "inr":
-		return "₹"
-	case "brl":
-		return "R$"
-	case "zar":
-		return "R"
-	default:
-		return "$"
-	}
+// GenerateRandomInvoiceData draws the invoice of the options. It fails with the
+// tax engine error when the vendor and customer regions can't be taxed, such
+// as tax.ErrUnknownRegion for a US customer without a valid state code.
+func GenerateRandomInvoiceData(options *GenerateInvoiceOptions) (InvoiceData, error) {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+	return generateInvoiceData(fake, options)
 }
 
-func getPaymentMethods(accountNumber int64, companyName, address string, ach, wire, check bool) []PaymentMethod {
-	paymentMethods := []PaymentMethod{}
-
-	if ach {
-		paymentMethods = append(paymentMethods, PaymentMethod{
-			Rail: "ACH",
-			Details: []InvoicePaymentDetails{
-				{Name: "Routing number", Value: "026001591"},
-				{Name: "Account number", Value: strconv.FormatInt(accountNumber, 10)},
-				{Name: "Beneficiary name", Value: companyName},
-			}})
-	}
+// generateInvoiceData draws an invoice from fake, which other documents
+// generated alongside the invoice keep drawing from.
+func generateInvoiceData(fake faker.Faker, options *GenerateInvoiceOptions) (InvoiceData, error) {
+	vendor := generateVendor(fake, options)
 
-	if wire {
-		paymentMethods = append(paymentMethods, PaymentMethod{
-			Rail: "Wire",
-			Details: []InvoicePaymentDetails{
-				{Name: "Bank name", Value: "Wells Fargo"},
-				{Name: "Routing number", Value: "121000248"},
-				{Name: "Account number", Value: strconv.FormatInt(accountNumber, 10)},
-				{Name: "Beneficiary name", Value: companyName},
-			}})
+	customer := options.Customer
+	if customer.Country == "" {
+		customer.Country = "US"
 	}
 
-	if check {
-		paymentMethods = append(paymentMethods, PaymentMethod{
-			Rail: "Check",
-			Details: []InvoicePaymentDetails{
-				{Name: "Payable to", Value: companyName},
-				{Name: "Address", Value: address},
-			}})
+	accountNumber := options.AccountNumber
+	if accountNumber == 0 {
+		// between 9 and 12 digits
+		accountNumber = fake.Int64Between(1e8, 1e12-1)
 	}
 
-	return paymentMethods
-}
-
-func generateInvoiceItems(numOfItems int, currency string) ([]InvoiceItem, string) {
-	fake := faker.New()
-	items := []InvoiceItem{}
-	total := 0.0
-	for i := 0; i < numOfItems; i++ {
-		price := fake.Float64(2, 100, 1000)
-		total += price
-		items = append(items, InvoiceItem{
-			Description: cases.Title(language.English).String(fake.Company().BS()),
-			Price:       fmt.Sprintf("%s%.2f", currency, price),
-		})
+	numberOfItems := options.NumberOfItems
+	if numberOfItems == 0 {
+		numberOfItems = fake.IntBetween(1, 8)
 	}
-	return items, fmt.Sprintf("%s%.2f", currency, total)
-}
 
-func includePaymentRails(rail string, options []string) bool {
-	return slices.Contains(options, rail)
-}
+	invoiceItems := generateInvoiceItems(fake, numberOfItems)
 
-func GenerateRandomInvoiceData(options *GenerateInvoiceOptions) InvoiceData {
-	fake := faker.New()
-	vendorName := options.VendorName
-	if vendorName == "" {
-		vendorName = fake.Company().Name()
+	rails := options.PaymentMethods
+	if len(rails) == 0 {
+		rails = defaultPaymentRails(vendor.Country)
 	}
-	vendorAddress := fake.Address()
-	currency := getCurrenctSymbol(options.Currency)
-
-	vendorStreetAddress := vendorAddress.StreetName() + " " + vendorAddress.StreetSuffix() + ", " + strconv.Itoa(fake.RandomNumber(3))
-	vendorCityStateZip := vendorAddress.City() + ", " + vendorAddress.StateAbbr() + " " + strings.Split(vendorAddress.PostCode(), "-")[0]
-	vendorFullAddress := vendorStreetAddress + ", " + vendorCityStateZip
-	vendorEmail := "bills@" + utils.TransformIntoValidEmailName(vendorName) + ".com"
-
-	accountNumber := options.AccountNumber
-
-	invoiceItems, total := generateInvoiceItems(options.NumberOfItems, currency)
-
-	includeAchRail := includePaymentRails("ach", options.PaymentMethods)
-	includeWireRail := includePaymentRails("wire", options.PaymentMethods)
-	includeCheckRail := includePaymentRails("check", options.PaymentMethods)
 
 	data := InvoiceData{
-		CompanyLogo: fmt.Sprintf("https://ui-avatars.com/api/?background=0D8ABC&color=fff&name=%s&rounded=true&size=16", strings.ReplaceAll(vendorName, " ", "+")),
+		CompanyLogo: logo.DataURI(vendor.Name),
 		// convert from int to string
 		InvoiceNumber: strconv.Itoa(fake.RandomNumber(5)),
 		// Invoice date should be today's date
 		InvoiceDate: options.InvoiceDate,
 		// Due date should be 30 days from today
-		DueDate: options.DueDate,
-		VendorInfo: CompanyInfo{
-			Name:          vendorName,
-			StreetAddress: vendorStreetAddress,
-			CityStateZip:  vendorCityStateZip,
-			Email:         vendorEmail,
-		},
-		CustomerInfo: CompanyInfo{
-			Name:          "Acme Corp.",
-			StreetAddress: "1234 Main St",
-			CityStateZip:  "San Francisco, CA 94111",
-			Email:         "mary@acme.com",
-		},
-		PaymentMethods: getPaymentMethods(accountNumber, vendorName, vendorFullAddress, includeAchRail, includeWireRail, includeCheckRail),
+		DueDate:        options.DueDate,
+		VendorInfo:     vendor,
+		PaymentMethods: getPaymentMethods(fake, rails, payee{vendor, accountNumber, strings.ToUpper(options.Currency)}),
+		Currency:       strings.ToUpper(options.Currency),
 		Items:          invoiceItems,
-		Total:          total,
 	}
 
-	return data
+	data.CustomerInfo = generateCustomer(fake, options, customer)
+	if options.ShipTo {
+		data.ShipTo = generateShipTo(fake, data.CustomerInfo)
+	}
+	if options.RemitTo {
+		data.RemitTo = generateRemitTo(fake, vendor)
+	}
+	data.CalculateTotals()
+
+	// jurisdictions without tax rules keep the randomly picked line rates
+	if options.TaxEngine != nil {
+		err := data.ApplyTax(options.TaxEngine)
+		if err != nil && !errors.Is(err, tax.ErrUnsupportedJurisdiction) {
+			return InvoiceData{}, err
+		}
+	}
+
+	return data, nil
+}
+
+// RenderOptions control how invoice data is presented. They never change the
+// data itself, only the display strings produced from it: Language picks the
+// labels, text direction and date format, Locale the number format. Annotate
+// adds the layout pass read back by annotate.Extract.
+type RenderOptions struct {
+	Language language.Tag
+	Locale   language.Tag
+	Annotate bool
+}
+
+// logoURL lets embedded images through html/template, which would otherwise
+// replace every data URI with "#ZgotmplZ". Other URLs are still filtered.
+func logoURL(src string) any {
+	if strings.HasPrefix(src, "data:image/") {
+		return template.URL(src)
+	}
+	return src
+}
+
+// formatDate renders an ISO 8601 date (2006-01-02) for the catalog. Dates in
+// any other format are printed as they are.
+func formatDate(catalog *i18n.Catalog, date string) string {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+
+	return catalog.FormatDate(t)
 }
 
-func GenerateInvoiceHtml(invoiceData *InvoiceData) (*os.File, error) {
-	templ, err := template.New(tmplFile).Funcs(template.FuncMap{
+func GenerateInvoiceHtml(invoiceData *InvoiceData, options *RenderOptions) (*os.File, error) {
+	return GenerateHtml(InvoiceTemplate, invoiceData, options)
+}
+
+// GenerateHtml renders the document template file, one of the *Template
+// constants, with data into a temporary HTML file, the input of the
+// Gotenberg conversions.
+func GenerateHtml(file string, data any, options *RenderOptions) (*os.File, error) {
+	catalog, _ := i18n.Match(options.Language)
+
+	templ, err := template.New(file).Funcs(template.FuncMap{
 		"nl2br": func(text string) template.HTML {
 			return template.HTML(strings.Replace(html.EscapeString(text), "\n", "<br>", -1))
 		},
 		"spacesToPlus": func(text string) string {
 			return strings.ReplaceAll(text, " ", "+")
 		},
-	}).ParseFiles(tmplFile)
+		"logoURL": logoURL,
+		"formatMoney": func(amount int64, currency string) string {
+			return money.Format(amount, currency, options.Locale)
+		},
+		"formatRate": func(rate money.Rate) string {
+			return money.FormatRate(rate, options.Locale)
+		},
+		"formatDate": func(date string) string {
+			return formatDate(catalog, date)
+		},
+		"annotate": func() bool {
+			return options.Annotate
+		},
+		"contentWidth": annotate.Letter.ContentWidth,
+		"titlePrefix": func() string {
+			return annotate.TitlePrefix
+		},
+		"t":    catalog.T,
+		"rtl":  catalog.RTL,
+		"lang": catalog.Tag.String,
+	}).ParseFiles(file)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing template template: %v", err)
 	}
 
-	tmpFile, err := os.CreateTemp("", "invoice.html")
+	tmpFile, err := os.CreateTemp("", strings.TrimSuffix(file, ".tmpl")+".html")
 	if err != nil {
 		return nil, fmt.Errorf("error creating index.html file: %v", err)
 	}
 	defer tmpFile.Close()
 
-	err = templ.Execute(tmpFile, invoiceData)
+	err = templ.Execute(tmpFile, data)
 	if err != nil {
 		return nil, fmt.Errorf("error executing template: %v", err)
 	}
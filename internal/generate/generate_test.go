@@ -0,0 +1,363 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/logo"
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+func TestInvoiceData_CalculateTotals(t *testing.T) {
+	data := InvoiceData{
+		Currency: "USD",
+		Items: []InvoiceItem{
+			{Description: "Consulting", Quantity: 3, Unit: "hrs", UnitPrice: 10010, TaxRate: 8250},
+			{Description: "Hosting", UnitPrice: 1999, Discount: Discount{Type: DiscountPercentage, Rate: 10000}},
+			{Description: "Support", Quantity: 10, UnitPrice: 100, Discount: Discount{Type: DiscountFixed, Amount: 250}, TaxRate: 10000, LineTotal: 999999},
+			{Description: "Refund", UnitPrice: 100, Discount: Discount{Type: DiscountFixed, Amount: 500}},
+		},
+		Total: 1,
+	}
+
+	data.CalculateTotals()
+
+	assert.Equal(t, data.Items[0].LineTotal, int64(30030))
+	assert.Equal(t, data.Items[0].TaxAmount, int64(2477))
+	assert.Equal(t, data.Items[1].Quantity, int64(1))
+	assert.Equal(t, data.Items[1].DiscountAmount, int64(200))
+	assert.Equal(t, data.Items[1].LineTotal, int64(1799))
+	assert.Equal(t, data.Items[2].LineTotal, int64(750))
+	assert.Equal(t, data.Items[2].TaxAmount, int64(75))
+	assert.Equal(t, data.Items[3].DiscountAmount, int64(100))
+	assert.Equal(t, data.Items[3].LineTotal, int64(0))
+
+	assert.Equal(t, data.Subtotal, int64(33129))
+	assert.Equal(t, data.DiscountTotal, int64(550))
+	assert.Equal(t, data.TaxTotal, int64(2552))
+	assert.Equal(t, data.Total, int64(35131))
+}
+
+func TestGenerateRandomInvoiceData_TotalMatchesItems(t *testing.T) {
+	data, err := GenerateRandomInvoiceData(&GenerateInvoiceOptions{
+		PaymentMethods: []string{"ach"},
+		NumberOfItems:  8,
+		Currency:       "usd",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sum int64
+	for _, item := range data.Items {
+		sum += item.LineTotal + item.TaxAmount
+	}
+
+	assert.Equal(t, len(data.Items), 8)
+	assert.Equal(t, data.Total, sum)
+	assert.Equal(t, data.Total, data.Subtotal-data.DiscountTotal+data.TaxTotal)
+	assert.Equal(t, data.Currency, "USD")
+}
+
+func TestInvoiceData_ApplyTax(t *testing.T) {
+	data := InvoiceData{
+		Currency:     "INR",
+		VendorInfo:   CompanyInfo{Country: "IN", Region: "KA"},
+		CustomerInfo: CompanyInfo{Country: "IN", Region: "KA"},
+		Items: []InvoiceItem{
+			{Description: "Consulting", Quantity: 2, UnitPrice: 50000, TaxRate: 5000},
+			{Description: "Hosting", UnitPrice: 20000, Discount: Discount{Type: DiscountFixed, Amount: 5000}},
+		},
+	}
+
+	err := data.ApplyTax(tax.NewEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, len(data.Taxes), 2)
+	assert.Equal(t, data.Taxes[0].Name, "CGST")
+	assert.Equal(t, data.Taxes[0].Amount, int64(10350))
+	assert.Equal(t, data.Taxes[1].Amount, int64(10350))
+	assert.Equal(t, data.Items[0].TaxRate, money.Rate(18000))
+	assert.Equal(t, data.TaxTotal, int64(20700))
+	assert.Equal(t, data.Total, int64(135700))
+
+	data.CustomerInfo = CompanyInfo{Country: "DE", TaxID: "DE123456789"}
+	err = data.ApplyTax(tax.NewEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, data.TaxTotal, int64(0))
+	assert.Equal(t, len(data.TaxNotes), 1)
+}
+
+func TestInvoiceData_ApplyTaxLineRounding(t *testing.T) {
+	data := InvoiceData{
+		Currency:     "INR",
+		VendorInfo:   CompanyInfo{Country: "IN", Region: "KA"},
+		CustomerInfo: CompanyInfo{Country: "IN", Region: "KA"},
+		Items: []InvoiceItem{
+			{Description: "Pens", UnitPrice: 2225},
+			{Description: "Paper", UnitPrice: 970},
+			{Description: "Stapler", UnitPrice: 1804},
+			{Description: "Ink", UnitPrice: 1920},
+			{Description: "Folders", UnitPrice: 1242},
+		},
+	}
+
+	err := data.ApplyTax(tax.NewEngine())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// rounded separately, the line taxes would add up to 1471 and the two
+	// halves of the GST to 1468
+	assert.Equal(t, data.TaxTotal, int64(1468))
+
+	var sum int64
+	for _, item := range data.Items {
+		exact := item.TaxRate.Of(item.LineTotal)
+		assert.Equal(t, item.TaxAmount >= exact-1 && item.TaxAmount <= exact+1, true)
+		sum += item.TaxAmount
+	}
+	assert.Equal(t, sum, data.TaxTotal)
+	assert.Equal(t, data.Total, data.Subtotal-data.DiscountTotal+data.TaxTotal)
+}
+
+// renderHtml fills template with data and returns the HTML. Templates are
+// resolved relative to the working directory, which is the repository root
+// when the server runs.
+func renderHtml(t *testing.T, template string, data any, options *RenderOptions) string {
+	t.Helper()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("../.."); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	file, err := GenerateHtml(template, data, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+
+	content, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestGenerateInvoiceHtml(t *testing.T) {
+	data := InvoiceData{
+		CompanyLogo:   logo.DataURI("Globex"),
+		InvoiceNumber: "10001",
+		InvoiceDate:   "2024-03-05",
+		DueDate:       "2024-04-04",
+		Currency:      "EUR",
+		VendorInfo:    CompanyInfo{Name: "Globex", Country: "DE", TaxID: "DE123456789"},
+		CustomerInfo:  CompanyInfo{Name: "Acme Corp.", Country: "FR", TaxID: "FR40303265045"},
+		ShipTo:        &CompanyInfo{Name: "Acme Receiving", StreetAddress: "1 Quai de Bercy", CityStateZip: "75012 Paris"},
+		Items: []InvoiceItem{
+			{Description: "Consulting", Quantity: 2, UnitPrice: 50000, Discount: Discount{Type: DiscountPercentage, Rate: 10000}},
+			{Description: "Hosting", UnitPrice: 20000, Discount: Discount{Type: DiscountFixed, Amount: 5000}},
+		},
+	}
+	if err := data.ApplyTax(tax.NewEngine()); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		language string
+		contains []string
+	}{
+		{"en", []string{`dir="ltr"`, "Invoice #", "March 5, 2024", "Ship to", "Acme Receiving", `src="data:image/svg`}},
+		{"de", []string{"Rechnung Nr.", "5. März 2024", "Gesamtbetrag"}},
+		{"ar", []string{`dir="rtl"`, `class="invoice-box rtl"`, "الإجمالي"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			tag := language.MustParse(tt.language)
+			content := renderHtml(t, InvoiceTemplate, &data, &RenderOptions{Language: tag, Locale: tag})
+
+			for _, s := range tt.contains {
+				assert.Equal(t, strings.Contains(content, s), true)
+			}
+		})
+	}
+}
+
+func TestGenerateRandomInvoiceData_Seed(t *testing.T) {
+	options := &GenerateInvoiceOptions{
+		Seed:           42,
+		PaymentMethods: []string{"ach", "wire", "check"},
+		AccountNumber:  123456789,
+		NumberOfItems:  5,
+		Currency:       "usd",
+		TaxEngine:      tax.NewEngine(),
+	}
+
+	first, err := GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmt.Sprintf("%+v", first), fmt.Sprintf("%+v", second))
+
+	options.Seed = 43
+	other, err := GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmt.Sprintf("%+v", first) == fmt.Sprintf("%+v", other), false)
+}
+
+func TestGenerateRandomInvoiceData_SeedPIX(t *testing.T) {
+	// every PIX key type, random keys included, comes up over these seeds
+	for seed := int64(0); seed < 20; seed++ {
+		options := &GenerateInvoiceOptions{
+			Seed:           seed,
+			PaymentMethods: []string{"pix"},
+			VendorCountry:  "BR",
+			Currency:       "brl",
+		}
+
+		first, err := GenerateRandomInvoiceData(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := GenerateRandomInvoiceData(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, fmt.Sprintf("%+v", first), fmt.Sprintf("%+v", second))
+	}
+}
+
+func TestGenerateRandomInvoiceData_TaxErrors(t *testing.T) {
+	options := &GenerateInvoiceOptions{
+		Seed:      1,
+		Currency:  "usd",
+		Customer:  tax.Jurisdiction{Country: "US", Region: "ZZ"},
+		TaxEngine: tax.NewEngine(),
+	}
+	_, err := GenerateRandomInvoiceData(options)
+	assert.Equal(t, errors.Is(err, tax.ErrUnknownRegion), true)
+
+	// vendors without tax rules keep the line rates
+	options.VendorCountry = "JP"
+	_, err = GenerateRandomInvoiceData(options)
+	assert.Equal(t, err, nil)
+
+	_, err = GenerateRandomReceiptData(&GenerateReceiptOptions{Seed: 1, Currency: "usd", MerchantRegion: "XX", TaxEngine: tax.NewEngine()})
+	assert.Equal(t, errors.Is(err, tax.ErrUnknownRegion), true)
+}
+
+func validInvoice() InvoiceData {
+	return InvoiceData{
+		InvoiceNumber: "10001",
+		InvoiceDate:   "2024-03-05",
+		DueDate:       "2024-04-04",
+		Currency:      "USD",
+		VendorInfo:    CompanyInfo{Name: "Globex", Email: "bills@globex.com"},
+		CustomerInfo:  CompanyInfo{Name: "Acme Corp.", Email: "mary@acme.com"},
+		Items: []InvoiceItem{
+			{Description: "Consulting", Quantity: 2, UnitPrice: 50000, TaxRate: 8250},
+		},
+	}
+}
+
+func TestValidateInvoice(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(d *InvoiceData)
+		errors []string
+	}{
+		{"Valid", func(d *InvoiceData) {}, nil},
+		{"Missing fields", func(d *InvoiceData) {
+			*d = InvoiceData{}
+		}, []string{"InvoiceNumber", "InvoiceDate", "DueDate", "VendorInfo.Name", "CustomerInfo.Name", "Currency", "Items"}},
+		{"Malformed email", func(d *InvoiceData) {
+			d.VendorInfo.Email = "bills at globex"
+		}, []string{"VendorInfo.Email"}},
+		{"Unparseable date", func(d *InvoiceData) {
+			d.InvoiceDate = "05/03/2024"
+		}, []string{"InvoiceDate"}},
+		{"Due before invoice date", func(d *InvoiceData) {
+			d.DueDate = "2024-03-04"
+		}, []string{"DueDate"}},
+		{"Unknown currency", func(d *InvoiceData) {
+			d.Currency = "XYZ"
+		}, []string{"Currency"}},
+		{"Invalid item", func(d *InvoiceData) {
+			d.Items = append(d.Items, InvoiceItem{Quantity: -1, TaxRate: 150000, Discount: Discount{Type: "bogo"}})
+		}, []string{"Items[1].Description", "Items[1].Quantity", "Items[1].TaxRate", "Items[1].Discount.Type"}},
+		{"Overflowing amounts", func(d *InvoiceData) {
+			d.Items[0].Quantity = math.MaxInt64
+			d.Items[0].UnitPrice = math.MaxInt64
+		}, []string{"Items[0].Quantity", "Items[0].UnitPrice"}},
+		{"Overflowing item total", func(d *InvoiceData) {
+			d.Items[0].Quantity = 1_000_000
+			d.Items[0].UnitPrice = 100_000_000
+		}, []string{"Items[0].UnitPrice"}},
+		{"Overflowing invoice total", func(d *InvoiceData) {
+			d.Items[0].Quantity = 1
+			d.Items[0].UnitPrice = money.MaxAmount
+			d.Items = append(d.Items, d.Items[0])
+		}, []string{"Items"}},
+		{"Invalid tax line", func(d *InvoiceData) {
+			d.Taxes = []tax.Line{{Name: "Sales tax", Rate: 8250}, {Rate: -1}, {Name: "VAT", Rate: 150000}}
+		}, []string{"Taxes[1].Name", "Taxes[1].Rate", "Taxes[2].Rate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := validInvoice()
+			tt.modify(&data)
+
+			v := validator.New()
+			ValidateInvoice(v, &data)
+
+			assert.Equal(t, len(v.Errors), len(tt.errors))
+			for _, key := range tt.errors {
+				assert.Equal(t, len(v.Errors[key]) > 0, true)
+			}
+		})
+	}
+}
+
+func TestValidateTotals(t *testing.T) {
+	sent := validInvoice()
+	calculated := validInvoice()
+	calculated.CalculateTotals()
+
+	v := validator.New()
+	ValidateTotals(v, &sent, &calculated)
+	assert.Equal(t, v.Valid(), true)
+
+	sent.Subtotal = calculated.Subtotal
+	sent.Total = 1
+	sent.Items[0].LineTotal = 99
+	ValidateTotals(v, &sent, &calculated)
+	assert.Equal(t, len(v.Errors), 2)
+	assert.Equal(t, v.Errors["Total"][0], validator.FieldError{Code: validator.CodeMismatch, Message: "must match the items (108250)"})
+	assert.Equal(t, len(v.Errors["Items[0].LineTotal"]), 1)
+}
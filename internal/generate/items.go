@@ -0,0 +1,159 @@
+package generate
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/jaswdr/faker/v2"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/tax"
+)
+
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+)
+
+// Discount is applied to a single line before tax. Percentage discounts use
+// Rate, fixed discounts use Amount in minor units.
+type Discount struct {
+	Type   string `validate:"oneof=percentage fixed"`
+	Rate   money.Rate
+	Amount int64 `validate:"min=0,max=10000000000000"`
+}
+
+// InvoiceItem prices are integer amounts in the minor unit of the invoice
+// currency, at most money.MaxAmount. DiscountAmount, TaxAmount and LineTotal
+// are always derived by CalculateTotals, never trusted from the client.
+type InvoiceItem struct {
+	Description    string `validate:"required,max=500"`
+	Quantity       int64  `validate:"min=0,max=1000000"`
+	Unit           string `validate:"max=32"`
+	UnitPrice      int64  `validate:"min=0,max=10000000000000"`
+	Discount       Discount
+	TaxRate        money.Rate
+	DiscountAmount int64
+	TaxAmount      int64
+	LineTotal      int64
+}
+
+// Gross is the line amount before the discount.
+func (i InvoiceItem) Gross() int64 {
+	return i.Quantity * i.UnitPrice
+}
+
+var itemUnits = []string{"ea", "hrs", "pcs", "licenses", "months", "days", "kg"}
+
+var taxRates = []money.Rate{0, 5000, 7250, 8250, 10000, 20000}
+
+func generateInvoiceItems(fake faker.Faker, numOfItems int) []InvoiceItem {
+	items := []InvoiceItem{}
+	taxRate := taxRates[fake.IntBetween(0, len(taxRates)-1)]
+	for i := 0; i < numOfItems; i++ {
+		item := InvoiceItem{
+			Description: cases.Title(language.English).String(fake.Company().BS()),
+			Quantity:    fake.Int64Between(1, 10),
+			Unit:        fake.RandomStringElement(itemUnits),
+			UnitPrice:   fake.Int64Between(1000, 50000),
+			TaxRate:     taxRate,
+		}
+
+		// roughly one in four lines gets a discount
+		switch fake.IntBetween(0, 7) {
+		case 0:
+			item.Discount = Discount{Type: DiscountPercentage, Rate: money.Rate(fake.IntBetween(1, 4) * 5000)}
+		case 1:
+			item.Discount = Discount{Type: DiscountFixed, Amount: fake.Int64Between(1, item.UnitPrice/10) * 10}
+		}
+
+		items = append(items, item)
+	}
+	return items
+}
+
+// CalculateTotals derives every line amount and the Subtotal / Discount /
+// Tax / Total breakdown from the item quantities, unit prices, discounts and
+// tax rates. Items without a quantity count as one.
+//
+// When the invoice carries separate tax lines (see ApplyTax), their amounts
+// are recomputed on the discounted subtotal and make up the tax total, and
+// the rounding difference is spread over the per-line taxes so they add up
+// to it; otherwise the tax total is the sum of the per-line taxes.
+func (d *InvoiceData) CalculateTotals() {
+	d.Subtotal, d.DiscountTotal, d.TaxTotal = calculateTotals(d.Items, d.Taxes)
+	d.Total = d.Subtotal - d.DiscountTotal + d.TaxTotal
+}
+
+// calculateTotals fills in the derived amounts of items and taxes and returns
+// the subtotal, discount and tax totals of any document made of line items.
+func calculateTotals(items []InvoiceItem, taxes []tax.Line) (subtotal, discountTotal, taxTotal int64) {
+	for i := range items {
+		item := &items[i]
+		if item.Quantity == 0 {
+			item.Quantity = 1
+		}
+
+		gross := item.Gross()
+
+		switch item.Discount.Type {
+		case DiscountPercentage:
+			item.DiscountAmount = item.Discount.Rate.Of(gross)
+		case DiscountFixed:
+			item.DiscountAmount = min(item.Discount.Amount, gross)
+		default:
+			item.DiscountAmount = 0
+		}
+
+		item.LineTotal = gross - item.DiscountAmount
+		item.TaxAmount = item.TaxRate.Of(item.LineTotal)
+
+		subtotal += gross
+		discountTotal += item.DiscountAmount
+		taxTotal += item.TaxAmount
+	}
+
+	if len(taxes) > 0 {
+		taxTotal = 0
+		for i := range taxes {
+			taxes[i].Amount = taxes[i].Rate.Of(subtotal - discountTotal)
+			taxTotal += taxes[i].Amount
+		}
+		allocateTax(items, taxTotal)
+	}
+
+	return subtotal, discountTotal, taxTotal
+}
+
+// allocateTax moves the difference between the tax lines, computed on the
+// whole subtotal, and the sum of the per-line taxes onto the lines whose tax
+// was rounded furthest the other way, a minor unit each, so the lines add up
+// to the tax total.
+func allocateTax(items []InvoiceItem, taxTotal int64) {
+	diff := taxTotal
+	for _, item := range items {
+		diff -= item.TaxAmount
+	}
+	if diff == 0 || len(items) == 0 {
+		return
+	}
+
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	// rounded down the most first
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(items[a].TaxRate.RoundingError(items[a].LineTotal), items[b].TaxRate.RoundingError(items[b].LineTotal))
+	})
+
+	unit := int64(1)
+	if diff < 0 {
+		unit, diff = -1, -diff
+		slices.Reverse(order)
+	}
+	for i := int64(0); i < diff; i++ {
+		items[order[i%int64(len(order))]].TaxAmount += unit
+	}
+}
@@ -0,0 +1,232 @@
+package generate
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/utils"
+)
+
+// defaultCustomer is who fake invoices are billed to unless the options say
+// otherwise. Its address is only kept in its own state, California.
+var defaultCustomer = CompanyInfo{
+	Name:          "Acme Corp.",
+	StreetAddress: "1234 Main St",
+	CityStateZip:  "San Francisco, CA 94111",
+	Email:         "mary@acme.com",
+	Country:       "US",
+	Region:        "CA",
+}
+
+// fakeAddress draws a US street address and city line in state, or in a
+// random state when state is empty.
+func fakeAddress(fake faker.Faker, state string) (street, cityStateZip string) {
+	address := fake.Address()
+	if state == "" {
+		state = address.StateAbbr()
+	}
+
+	street = strconv.Itoa(fake.IntBetween(1, 9999)) + " " + address.StreetName() + " " + address.StreetSuffix()
+	cityStateZip = address.City() + ", " + state + " " + strings.Split(address.PostCode(), "-")[0]
+
+	return street, cityStateZip
+}
+
+// partyAddress draws an address in country, and for US addresses in region,
+// see fakeAddress and foreignAddress.
+func partyAddress(fake faker.Faker, country, region string) (street, city string) {
+	if country != "US" {
+		return foreignAddress(fake, country)
+	}
+	return fakeAddress(fake, region)
+}
+
+// postalFormat is how the city line of an address is written in a country:
+// a city drawn from cities and a postcode pattern, # standing for a digit
+// and ? for a letter, printed before the city unless after is set.
+type postalFormat struct {
+	cities   []string
+	postcode string
+	after    bool
+}
+
+var postalFormats = map[string]postalFormat{
+	"AT": {[]string{"Wien", "Graz", "Linz", "Salzburg"}, "####", false},
+	"AU": {[]string{"Sydney NSW", "Melbourne VIC", "Brisbane QLD", "Perth WA"}, "####", true},
+	"BE": {[]string{"Bruxelles", "Antwerpen", "Gent", "Liège"}, "####", false},
+	"BG": {[]string{"Sofia", "Plovdiv", "Varna"}, "####", false},
+	"BR": {[]string{"São Paulo - SP", "Rio de Janeiro - RJ", "Belo Horizonte - MG", "Curitiba - PR"}, "#####-###", true},
+	"CA": {[]string{"Toronto ON", "Vancouver BC", "Montréal QC", "Calgary AB"}, "?#? #?#", true},
+	"CH": {[]string{"Zürich", "Genève", "Basel", "Bern"}, "####", false},
+	"CN": {[]string{"Beijing", "Shanghai", "Shenzhen", "Guangzhou"}, "######", true},
+	"CY": {[]string{"Nicosia", "Limassol", "Larnaca"}, "####", false},
+	"CZ": {[]string{"Praha", "Brno", "Ostrava"}, "### ##", false},
+	"DE": {[]string{"Berlin", "Hamburg", "München", "Köln", "Frankfurt am Main"}, "#####", false},
+	"DK": {[]string{"København", "Aarhus", "Odense"}, "####", false},
+	"EE": {[]string{"Tallinn", "Tartu"}, "#####", false},
+	"ES": {[]string{"Madrid", "Barcelona", "Valencia", "Sevilla"}, "#####", false},
+	"FI": {[]string{"Helsinki", "Espoo", "Tampere"}, "#####", false},
+	"FR": {[]string{"Paris", "Lyon", "Marseille", "Toulouse", "Bordeaux"}, "#####", false},
+	"GB": {[]string{"London", "Manchester", "Birmingham", "Bristol", "Leeds"}, "??# #??", true},
+	"GR": {[]string{"Athina", "Thessaloniki", "Patra"}, "### ##", false},
+	"HU": {[]string{"Budapest", "Debrecen", "Szeged"}, "####", false},
+	"IE": {[]string{"Dublin", "Cork", "Galway"}, "?## ?#?#", true},
+	"IL": {[]string{"Tel Aviv", "Jerusalem", "Haifa"}, "#######", true},
+	"IN": {[]string{"Mumbai", "Bengaluru", "New Delhi", "Chennai"}, "######", true},
+	"IS": {[]string{"Reykjavík", "Kópavogur"}, "###", false},
+	"IT": {[]string{"Roma", "Milano", "Torino", "Napoli"}, "#####", false},
+	"JP": {[]string{"Tokyo", "Osaka", "Yokohama", "Nagoya"}, "###-####", true},
+	"KR": {[]string{"Seoul", "Busan", "Incheon"}, "#####", true},
+	"LT": {[]string{"Vilnius", "Kaunas"}, "LT-#####", false},
+	"LU": {[]string{"Luxembourg", "Esch-sur-Alzette"}, "L-####", false},
+	"LV": {[]string{"Rīga", "Daugavpils"}, "LV-####", false},
+	"MT": {[]string{"Valletta", "Sliema", "Birkirkara"}, "??? ####", true},
+	"MX": {[]string{"Ciudad de México, CDMX", "Guadalajara, Jal.", "Monterrey, N.L."}, "#####", false},
+	"NL": {[]string{"Amsterdam", "Rotterdam", "Utrecht", "Den Haag"}, "#### ??", false},
+	"NO": {[]string{"Oslo", "Bergen", "Trondheim"}, "####", false},
+	"NZ": {[]string{"Auckland", "Wellington", "Christchurch"}, "####", true},
+	"PL": {[]string{"Warszawa", "Kraków", "Wrocław", "Gdańsk"}, "##-###", false},
+	"PT": {[]string{"Lisboa", "Porto", "Braga"}, "####-###", false},
+	"RO": {[]string{"București", "Cluj-Napoca", "Timișoara"}, "######", false},
+	"SE": {[]string{"Stockholm", "Göteborg", "Malmö"}, "### ##", false},
+	"SI": {[]string{"Ljubljana", "Maribor"}, "####", false},
+	"SK": {[]string{"Bratislava", "Košice"}, "### ##", false},
+	"TR": {[]string{"İstanbul", "Ankara", "İzmir"}, "#####", false},
+	"ZA": {[]string{"Cape Town", "Johannesburg", "Durban"}, "####", true},
+}
+
+// houseNumberFirst lists the countries writing the house number before the
+// street name, as in the US.
+var houseNumberFirst = []string{"AU", "CA", "GB", "IE", "IL", "IN", "MT", "NZ", "ZA"}
+
+// foreignAddress draws a street address and city line laid out the way they
+// are in country. Countries missing from postalFormats get a city without
+// postcode.
+func foreignAddress(fake faker.Faker, country string) (street, city string) {
+	address := fake.Address()
+	number := strconv.Itoa(fake.IntBetween(1, 200))
+
+	street = address.StreetName() + " " + number
+	if slices.Contains(houseNumberFirst, country) {
+		street = number + " " + address.StreetName()
+	}
+
+	format, ok := postalFormats[country]
+	if !ok {
+		return street, address.City()
+	}
+
+	city = fake.RandomStringElement(format.cities)
+	postcode := strings.ToUpper(fake.Bothify(format.postcode))
+	if format.after {
+		return street, city + " " + postcode
+	}
+	return street, postcode + " " + city
+}
+
+// generateVendor draws the identity of the vendor of the options: its name,
+// address and tax jurisdiction. Every document about the vendor starts from
+// it, so an invoice and a tax form drawn from the same seed name the same
+// company at the same address.
+func generateVendor(fake faker.Faker, options *GenerateInvoiceOptions) CompanyInfo {
+	name := options.VendorName
+	if name == "" {
+		name = fake.Company().Name()
+	}
+	address := fake.Address()
+	state := address.StateAbbr()
+
+	vendor := CompanyInfo{
+		Name:          name,
+		StreetAddress: address.StreetName() + " " + address.StreetSuffix() + ", " + strconv.Itoa(fake.RandomNumber(3)),
+		CityStateZip:  address.City() + ", " + state + " " + strings.Split(address.PostCode(), "-")[0],
+		Email:         "bills@" + utils.TransformIntoValidEmailName(name) + ".com",
+		Country:       strings.ToUpper(options.VendorCountry),
+		Region:        strings.ToUpper(options.VendorRegion),
+	}
+
+	if vendor.Country == "" {
+		vendor.Country = "US"
+	}
+	if vendor.Region == "" && vendor.Country == "US" {
+		vendor.Region = state
+	}
+	if vendor.Country != "US" {
+		vendor.TaxID = vendor.Country + strconv.Itoa(fake.RandomNumber(9))
+		vendor.StreetAddress, vendor.CityStateZip = foreignAddress(fake, vendor.Country)
+	}
+
+	return vendor
+}
+
+// generateCustomer builds the bill-to party from the options, placed in the
+// customer tax jurisdiction. A US customer without a state is Acme Corp. in
+// California, or a random customer in a random state; the address printed is
+// always in the state or country the invoice is taxed for.
+func generateCustomer(fake faker.Faker, options *GenerateInvoiceOptions, jurisdiction tax.Jurisdiction) CompanyInfo {
+	customer := defaultCustomer
+	customer.Country = strings.ToUpper(jurisdiction.Country)
+	customer.Region = strings.ToUpper(jurisdiction.Region)
+	customer.TaxID = jurisdiction.TaxID
+
+	if options.RandomCustomer {
+		customer.Name = fake.Company().Name()
+		customer.Email = "ap@" + utils.TransformIntoValidEmailName(customer.Name) + ".com"
+	}
+
+	switch {
+	case customer.Country != "US":
+		customer.StreetAddress, customer.CityStateZip = foreignAddress(fake, customer.Country)
+	case options.RandomCustomer:
+		if customer.Region == "" {
+			customer.Region = fake.Address().StateAbbr()
+		}
+		customer.StreetAddress, customer.CityStateZip = fakeAddress(fake, customer.Region)
+	case customer.Region == "":
+		customer.Region = defaultCustomer.Region
+	case customer.Region != defaultCustomer.Region:
+		customer.StreetAddress, customer.CityStateZip = fakeAddress(fake, customer.Region)
+	}
+
+	if options.CustomerName != "" {
+		customer.Name = options.CustomerName
+	}
+	if options.CustomerAddress != "" {
+		customer.StreetAddress = options.CustomerAddress
+	}
+	if options.CustomerCity != "" {
+		customer.CityStateZip = options.CustomerCity
+	}
+	if options.CustomerEmail != "" {
+		customer.Email = options.CustomerEmail
+	}
+
+	return customer
+}
+
+// generateShipTo draws the warehouse the customer has the goods delivered to.
+func generateShipTo(fake faker.Faker, customer CompanyInfo) *CompanyInfo {
+	shipTo := &CompanyInfo{
+		Name:    customer.Name + " Receiving",
+		Country: customer.Country,
+	}
+	shipTo.StreetAddress, shipTo.CityStateZip = partyAddress(fake, customer.Country, customer.Region)
+
+	return shipTo
+}
+
+// generateRemitTo draws the lockbox the vendor collects check payments at.
+func generateRemitTo(fake faker.Faker, vendor CompanyInfo) *CompanyInfo {
+	remitTo := &CompanyInfo{
+		Name:          vendor.Name,
+		StreetAddress: "PO Box " + fake.Numerify("#####"),
+		Email:         vendor.Email,
+		Country:       vendor.Country,
+	}
+	_, remitTo.CityStateZip = partyAddress(fake, vendor.Country, vendor.Region)
+
+	return remitTo
+}
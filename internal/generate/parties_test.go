@@ -0,0 +1,100 @@
+package generate
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/tax"
+)
+
+func TestGenerateRandomInvoiceData_Customer(t *testing.T) {
+	data, err := GenerateRandomInvoiceData(&GenerateInvoiceOptions{Seed: 1, Currency: "usd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, data.CustomerInfo.Name, "Acme Corp.")
+	assert.Equal(t, data.ShipTo == nil, true)
+	assert.Equal(t, data.RemitTo == nil, true)
+
+	options := &GenerateInvoiceOptions{
+		Seed:           1,
+		Currency:       "usd",
+		RandomCustomer: true,
+		CustomerEmail:  "payables@initech.com",
+		ShipTo:         true,
+		RemitTo:        true,
+	}
+	data, err = GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, data.CustomerInfo.Name != "Acme Corp.", true)
+	assert.Equal(t, data.CustomerInfo.StreetAddress != defaultCustomer.StreetAddress, true)
+	assert.Equal(t, data.CustomerInfo.Email, "payables@initech.com")
+	assert.Equal(t, data.ShipTo.Name, data.CustomerInfo.Name+" Receiving")
+	assert.Equal(t, data.RemitTo.Name, data.VendorInfo.Name)
+
+	options.Seed = 2
+	other, err := GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, other.CustomerInfo.Name != data.CustomerInfo.Name, true)
+
+	options.CustomerName = "Initech"
+	data, err = GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, data.CustomerInfo.Name, "Initech")
+}
+
+func TestGenerateRandomInvoiceData_CustomerAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		random   bool
+		customer tax.Jurisdiction
+		region   string
+		city     string
+	}{
+		{name: "Acme", customer: tax.Jurisdiction{Country: "US"}, region: "CA", city: "San Francisco, CA 94111"},
+		{name: "Acme in Texas", customer: tax.Jurisdiction{Country: "US", Region: "TX"}, region: "TX", city: ", TX "},
+		{name: "Random customer", random: true, customer: tax.Jurisdiction{Country: "US"}},
+		{name: "Random customer in Texas", random: true, customer: tax.Jurisdiction{Country: "US", Region: "tx"}, region: "TX", city: ", TX "},
+		{name: "German customer", customer: tax.Jurisdiction{Country: "DE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for seed := int64(1); seed <= 5; seed++ {
+				data, err := GenerateRandomInvoiceData(&GenerateInvoiceOptions{
+					Seed:           seed,
+					Currency:       "usd",
+					Customer:       tt.customer,
+					RandomCustomer: tt.random,
+					ShipTo:         true,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				customer := data.CustomerInfo
+				assert.Equal(t, customer.Country, tt.customer.Country)
+				if tt.region != "" {
+					assert.Equal(t, customer.Region, tt.region)
+					assert.Equal(t, strings.Contains(customer.CityStateZip, tt.city), true)
+				}
+				if customer.Country == "US" {
+					// the state printed is the one taxed
+					assert.Equal(t, strings.Contains(customer.CityStateZip, ", "+customer.Region+" "), true)
+					assert.Equal(t, strings.Contains(data.ShipTo.CityStateZip, ", "+customer.Region+" "), true)
+				} else {
+					assert.Equal(t, customer.StreetAddress != defaultCustomer.StreetAddress, true)
+					assert.Equal(t, regexp.MustCompile(`^\d{5} `).MatchString(customer.CityStateZip), true)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package generate
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/banking"
+)
+
+// PaymentRails lists the rails accepted in GenerateInvoiceOptions
+// PaymentMethods, in the order their blocks are rendered.
+var PaymentRails = []string{"ach", "wire", "check", "sepa", "swift", "pix", "fps", "eft"}
+
+// payee is the party a payment rail pays out to, the vendor of the invoice,
+// and the currency it is paid in.
+type payee struct {
+	CompanyInfo
+	accountNumber int64
+	currency      string
+}
+
+func (p payee) address() string {
+	return p.StreetAddress + ", " + p.CityStateZip
+}
+
+type railFunc func(fake faker.Faker, p payee) PaymentMethod
+
+var paymentRails = map[string]railFunc{
+	"ach":   achRail,
+	"wire":  wireRail,
+	"check": checkRail,
+	"sepa":  sepaRail,
+	"swift": swiftRail,
+	"pix":   pixRail,
+	"fps":   fasterPaymentsRail,
+	"eft":   eftRail,
+}
+
+// sepaCountries are the countries in the SEPA scheme, apart from the United
+// Kingdom whose vendors are paid over Faster Payments domestically.
+var sepaCountries = []string{
+	"AT", "BE", "BG", "CH", "CY", "CZ", "DE", "DK", "EE", "ES", "FI", "FR", "GR",
+	"HR", "HU", "IE", "IS", "IT", "LI", "LT", "LU", "LV", "MC", "MT", "NL", "NO",
+	"PL", "PT", "RO", "SE", "SI", "SK", "SM",
+}
+
+// defaultPaymentRails picks the rail a vendor in country would put on its
+// invoices when the options don't ask for any.
+func defaultPaymentRails(country string) []string {
+	switch {
+	case country == "US":
+		return []string{"ach"}
+	case country == "BR":
+		return []string{"pix"}
+	case country == "GB":
+		return []string{"fps"}
+	case country == "CA":
+		return []string{"eft"}
+	case slices.Contains(sepaCountries, country):
+		return []string{"sepa"}
+	default:
+		return []string{"swift"}
+	}
+}
+
+func getPaymentMethods(fake faker.Faker, rails []string, p payee) []PaymentMethod {
+	paymentMethods := []PaymentMethod{}
+
+	for _, rail := range PaymentRails {
+		if slices.Contains(rails, rail) {
+			paymentMethods = append(paymentMethods, paymentRails[rail](fake, p))
+		}
+	}
+
+	return paymentMethods
+}
+
+func achRail(fake faker.Faker, p payee) PaymentMethod {
+	bank, _ := banking.RandomBank(fake, "US")
+
+	return PaymentMethod{
+		Rail: "ACH",
+		Details: []InvoicePaymentDetails{
+			{Name: "Bank name", Value: bank.Name},
+			{Name: "Routing number", Value: banking.RoutingNumber(fake, bank)},
+			{Name: "Account number", Value: strconv.FormatInt(p.accountNumber, 10)},
+			{Name: "Beneficiary name", Value: p.Name},
+		}}
+}
+
+func wireRail(fake faker.Faker, p payee) PaymentMethod {
+	bank, _ := banking.RandomBank(fake, "US")
+
+	return PaymentMethod{
+		Rail: "Wire",
+		Details: []InvoicePaymentDetails{
+			{Name: "Bank name", Value: bank.Name},
+			{Name: "Routing number", Value: banking.RoutingNumber(fake, bank)},
+			{Name: "Account number", Value: strconv.FormatInt(p.accountNumber, 10)},
+			{Name: "Beneficiary name", Value: p.Name},
+		}}
+}
+
+func checkRail(fake faker.Faker, p payee) PaymentMethod {
+	return PaymentMethod{
+		Rail: "Check",
+		Details: []InvoicePaymentDetails{
+			{Name: "Payable to", Value: p.Name},
+			{Name: "Address", Value: p.address()},
+		}}
+}
+
+// iban generates the printed IBAN of an account at bank.
+func iban(fake faker.Faker, bank banking.Bank) string {
+	iban, err := banking.IBAN(fake, bank)
+	if err != nil {
+		// callers only pass banks of IBAN countries
+		panic(err)
+	}
+	return banking.FormatIBAN(iban)
+}
+
+func sepaRail(fake faker.Faker, p payee) PaymentMethod {
+	// vendors outside SEPA, or in a SEPA country without known banks, keep
+	// their euro account in Germany
+	bank, ok := banking.RandomBank(fake, p.Country)
+	if !ok || !slices.Contains(sepaCountries, p.Country) {
+		bank, _ = banking.RandomBank(fake, "DE")
+	}
+
+	return PaymentMethod{
+		Rail: "SEPA Credit Transfer",
+		Details: []InvoicePaymentDetails{
+			{Name: "Bank name", Value: bank.Name},
+			{Name: "IBAN", Value: iban(fake, bank)},
+			{Name: "BIC", Value: bank.BIC},
+			{Name: "Beneficiary name", Value: p.Name},
+		}}
+}
+
+func swiftRail(fake faker.Faker, p payee) PaymentMethod {
+	details := []InvoicePaymentDetails{}
+
+	bank, ok := banking.RandomBank(fake, p.Country)
+	if ok {
+		details = append(details, InvoicePaymentDetails{Name: "Bank name", Value: bank.Name})
+	} else {
+		bank = banking.Bank{Country: p.Country, BIC: banking.BIC(fake, p.Country)}
+	}
+	details = append(details, InvoicePaymentDetails{Name: "SWIFT code", Value: bank.BIC})
+
+	if banking.HasIBAN(p.Country) {
+		details = append(details, InvoicePaymentDetails{Name: "IBAN", Value: iban(fake, bank)})
+	} else {
+		details = append(details, InvoicePaymentDetails{Name: "Account number", Value: strconv.FormatInt(p.accountNumber, 10)})
+	}
+
+	details = append(details,
+		InvoicePaymentDetails{Name: "Beneficiary name", Value: p.Name},
+		InvoicePaymentDetails{Name: "Beneficiary address", Value: p.address()},
+	)
+
+	// USD wires to banks abroad usually clear through a US correspondent
+	if p.currency == "USD" && bank.Country != "US" {
+		details = append(details,
+			InvoicePaymentDetails{Name: "Intermediary bank", Value: "JPMorgan Chase Bank, N.A."},
+			InvoicePaymentDetails{Name: "Intermediary SWIFT code", Value: "CHASUS33"},
+		)
+	}
+
+	return PaymentMethod{
+		Rail:    "International wire (SWIFT)",
+		Details: details,
+	}
+}
+
+func pixRail(fake faker.Faker, p payee) PaymentMethod {
+	var keyType, key string
+
+	switch fake.IntBetween(0, 3) {
+	case 0:
+		keyType, key = "CNPJ", fakeCNPJ(fake)
+	case 1:
+		keyType, key = "E-mail", p.Email
+	case 2:
+		keyType, key = "Phone", fake.Numerify("+55 11 9####-####")
+	default:
+		keyType, key = "Random key", fakeUUID(fake)
+	}
+
+	return PaymentMethod{
+		Rail: "PIX",
+		Details: []InvoicePaymentDetails{
+			{Name: "Key type", Value: keyType},
+			{Name: "PIX key", Value: key},
+			{Name: "Beneficiary name", Value: p.Name},
+		}}
+}
+
+// fakeUUID draws a version 4 UUID from fake. faker's own UUID generator reads
+// crypto/rand, which would make seeded invoices irreproducible.
+func fakeUUID(fake faker.Faker) string {
+	const hex = "0123456789abcdef"
+
+	var s strings.Builder
+	for i := 0; i < 32; i++ {
+		switch i {
+		case 8, 12, 16, 20:
+			s.WriteByte('-')
+		}
+
+		switch i {
+		case 12:
+			// version 4
+			s.WriteByte('4')
+		case 16:
+			// RFC 4122 variant, 10xx
+			s.WriteByte(hex[8+fake.IntBetween(0, 3)])
+		default:
+			s.WriteByte(hex[fake.IntBetween(0, 15)])
+		}
+	}
+	return s.String()
+}
+
+// fakeCNPJ builds a Brazilian company registration number (the most common
+// PIX key for businesses) with valid check digits.
+func fakeCNPJ(fake faker.Faker) string {
+	digits := make([]int, 0, 14)
+	for i := 0; i < 8; i++ {
+		digits = append(digits, fake.RandomDigit())
+	}
+	// 0001 is the head office
+	digits = append(digits, 0, 0, 0, 1)
+
+	for _, weights := range [][]int{
+		{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2},
+		{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2},
+	} {
+		sum := 0
+		for i, w := range weights {
+			sum += digits[i] * w
+		}
+		check := 11 - sum%11
+		if check >= 10 {
+			check = 0
+		}
+		digits = append(digits, check)
+	}
+
+	var s strings.Builder
+	for i, d := range digits {
+		switch i {
+		case 2, 5:
+			s.WriteByte('.')
+		case 8:
+			s.WriteByte('/')
+		case 12:
+			s.WriteByte('-')
+		}
+		s.WriteString(strconv.Itoa(d))
+	}
+	return s.String()
+}
+
+func fasterPaymentsRail(fake faker.Faker, p payee) PaymentMethod {
+	bank, _ := banking.RandomBank(fake, "GB")
+
+	return PaymentMethod{
+		Rail: "UK Faster Payments",
+		Details: []InvoicePaymentDetails{
+			{Name: "Bank name", Value: bank.Name},
+			{Name: "Sort code", Value: bank.NationalCode + fake.Numerify("-##-##")},
+			{Name: "Account number", Value: fake.Numerify("########")},
+			{Name: "Beneficiary name", Value: p.Name},
+		}}
+}
+
+func eftRail(fake faker.Faker, p payee) PaymentMethod {
+	bank, _ := banking.RandomBank(fake, "CA")
+
+	return PaymentMethod{
+		Rail: "Canadian EFT",
+		Details: []InvoicePaymentDetails{
+			{Name: "Bank name", Value: bank.Name},
+			{Name: "Institution number", Value: bank.NationalCode},
+			{Name: "Transit number", Value: fake.Numerify("#####")},
+			{Name: "Account number", Value: fake.Numerify("#######")},
+			{Name: "Beneficiary name", Value: p.Name},
+		}}
+}
@@ -0,0 +1,138 @@
+package generate
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/banking"
+)
+
+func TestGenerateRandomInvoiceData_PaymentRails(t *testing.T) {
+	tests := []struct {
+		name    string
+		country string
+		rails   []string
+		want    []string
+	}{
+		{"US default", "US", nil, []string{"ACH"}},
+		{"Brazil default", "BR", nil, []string{"PIX"}},
+		{"UK default", "GB", nil, []string{"UK Faster Payments"}},
+		{"Canada default", "CA", nil, []string{"Canadian EFT"}},
+		{"SEPA default", "FR", nil, []string{"SEPA Credit Transfer"}},
+		{"Other default", "JP", nil, []string{"International wire (SWIFT)"}},
+		{"US rails", "US", []string{"wire", "ach"}, []string{"ACH", "Wire"}},
+		{"Rendered in rail order", "DE", []string{"swift", "sepa", "check"}, []string{"Check", "SEPA Credit Transfer", "International wire (SWIFT)"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := GenerateRandomInvoiceData(&GenerateInvoiceOptions{
+				Seed:           1,
+				PaymentMethods: tt.rails,
+				VendorCountry:  tt.country,
+				Currency:       "usd",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got []string
+			for _, method := range data.PaymentMethods {
+				got = append(got, method.Rail)
+				assert.Equal(t, len(method.Details) > 0, true)
+
+				var bank banking.Bank
+				for _, detail := range method.Details {
+					switch detail.Name {
+					case "Bank name":
+						for _, b := range banking.Banks(tt.country) {
+							if b.Name == detail.Value {
+								bank = b
+							}
+						}
+					case "IBAN":
+						assert.Equal(t, banking.ValidIBAN(detail.Value), true)
+					case "Routing number":
+						// the routing number is the one of the bank named
+						assert.Equal(t, banking.ValidRoutingNumber(detail.Value), true)
+						assert.Equal(t, strings.HasPrefix(detail.Value, bank.NationalCode), true)
+						assert.Equal(t, bank.NationalCode != "", true)
+					case "BIC", "SWIFT code":
+						assert.Equal(t, banking.ValidBIC(detail.Value), true)
+					}
+				}
+			}
+			assert.Equal(t, strings.Join(got, ","), strings.Join(tt.want, ","))
+		})
+	}
+}
+
+func TestSwiftRail_Intermediary(t *testing.T) {
+	tests := []struct {
+		name     string
+		country  string
+		currency string
+		want     bool
+	}{
+		{"USD abroad", "JP", "USD", true},
+		{"Local currency", "JP", "JPY", false},
+		{"EUR abroad", "DE", "EUR", false},
+		{"USD to a US bank", "US", "USD", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := faker.NewWithSeed(rand.NewSource(1))
+			method := swiftRail(fake, payee{CompanyInfo{Name: "Globex", Country: tt.country}, 123456789, tt.currency})
+
+			got := slices.ContainsFunc(method.Details, func(detail InvoicePaymentDetails) bool {
+				return detail.Name == "Intermediary SWIFT code"
+			})
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestFakeUUID(t *testing.T) {
+	fake := faker.NewWithSeed(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		uuid := fakeUUID(fake)
+		assert.Equal(t, len(uuid), 36)
+		assert.Equal(t, uuid[14], byte('4'))
+		assert.Equal(t, strings.ContainsRune("89ab", rune(uuid[19])), true)
+	}
+}
+
+func TestFakeCNPJ(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		cnpj := fakeCNPJ(faker.NewWithSeed(rand.NewSource(seed)))
+		assert.Equal(t, len(cnpj), len("12.345.678/0001-95"))
+
+		var digits []int
+		for _, c := range cnpj {
+			if c >= '0' && c <= '9' {
+				digits = append(digits, int(c-'0'))
+			}
+		}
+
+		// a valid CNPJ passes both weighted sums again with its check digits
+		for n, first := range []int{5, 6} {
+			sum, w := 0, first
+			for _, d := range digits[:12+n] {
+				sum += d * w
+				if w--; w < 2 {
+					w = 9
+				}
+			}
+			check := 11 - sum%11
+			if check >= 10 {
+				check = 0
+			}
+			assert.Equal(t, digits[12+n], check)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package generate
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/logo"
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+const (
+	DiscrepancyQuantity = "quantity"
+	DiscrepancyPrice    = "price"
+)
+
+// Discrepancies lists the kinds of differences GenerateMatchSet can plant
+// between the documents of a set.
+var Discrepancies = []string{DiscrepancyQuantity, DiscrepancyPrice}
+
+// PurchaseOrderData is the order a customer places with a vendor, the first
+// leg of a three-way match. It shares the parties and line items of
+// InvoiceData, amounts follow the same rules.
+type PurchaseOrderData struct {
+	CompanyLogo   string
+	PONumber      string `validate:"required,max=64"`
+	OrderDate     string `validate:"required,date"`
+	DeliveryDate  string `validate:"date"`
+	PaymentTerms  string `validate:"max=64"`
+	VendorInfo    CompanyInfo
+	CustomerInfo  CompanyInfo
+	ShipTo        *CompanyInfo  `json:",omitempty"`
+	Currency      string        `validate:"required,currency"`
+	Items         []InvoiceItem `validate:"required,dive"`
+	Subtotal      int64
+	DiscountTotal int64
+	Taxes         []tax.Line
+	TaxNotes      []string
+	TaxTotal      int64
+	Total         int64
+}
+
+// ReceivedItem is a purchase order line as counted on arrival.
+type ReceivedItem struct {
+	Description string
+	Unit        string
+	Ordered     int64
+	Received    int64
+}
+
+// GoodsReceiptData records what the customer's warehouse received against a
+// purchase order, the second leg of a three-way match.
+type GoodsReceiptData struct {
+	ReceiptNumber string
+	PONumber      string
+	ReceivedDate  string
+	VendorInfo    CompanyInfo
+	CustomerInfo  CompanyInfo
+	ShipTo        *CompanyInfo `json:",omitempty"`
+	Items         []ReceivedItem
+}
+
+// Discrepancy is a difference planted in one document of a MatchSet: Line of
+// Document ("goodsReceipt" or "invoice") carries Actual where the purchase
+// order says Expected.
+type Discrepancy struct {
+	Type        string
+	Document    string
+	Line        int
+	Description string
+	Expected    int64
+	Actual      int64
+}
+
+// MatchSet is a purchase order with the goods receipt and invoice that match
+// it, apart from the listed Discrepancies.
+type MatchSet struct {
+	PurchaseOrder PurchaseOrderData
+	GoodsReceipt  GoodsReceiptData
+	Invoice       InvoiceData
+	Discrepancies []Discrepancy
+}
+
+const (
+	PurchaseOrderTemplate = "purchase_order.tmpl"
+	GoodsReceiptTemplate  = "goods_receipt.tmpl"
+)
+
+// GenerateRandomPurchaseOrderData draws a purchase order from the invoice
+// options: InvoiceDate is the order date and the days until DueDate become
+// the payment terms. The order is the same as in the match set of the seed.
+func GenerateRandomPurchaseOrderData(options *GenerateInvoiceOptions) (PurchaseOrderData, error) {
+	set, err := GenerateMatchSet(options, nil)
+	return set.PurchaseOrder, err
+}
+
+// GenerateMatchSet draws a purchase order like GenerateRandomPurchaseOrderData,
+// then the goods receipt of its delivery and the invoice billing it. Each kind
+// of discrepancy asked for is planted once: a short delivery on the goods
+// receipt, or a raised unit price on the invoice. It fails when the invoice
+// does.
+func GenerateMatchSet(options *GenerateInvoiceOptions, discrepancies []string) (MatchSet, error) {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+	invoice, err := generateInvoiceData(fake, options)
+	if err != nil {
+		return MatchSet{}, err
+	}
+
+	terms := daysBetween(invoice.InvoiceDate, invoice.DueDate)
+
+	po := PurchaseOrderData{
+		// purchase orders are issued by the customer
+		CompanyLogo:  logo.DataURI(invoice.CustomerInfo.Name),
+		PONumber:     "PO-" + fake.Numerify("######"),
+		OrderDate:    invoice.InvoiceDate,
+		DeliveryDate: addDays(invoice.InvoiceDate, fake.IntBetween(3, 14)),
+		PaymentTerms: fmt.Sprintf("Net %d", terms),
+		VendorInfo:   invoice.VendorInfo,
+		CustomerInfo: invoice.CustomerInfo,
+		ShipTo:       invoice.ShipTo,
+		Currency:     invoice.Currency,
+		Items:        slices.Clone(invoice.Items),
+		Taxes:        slices.Clone(invoice.Taxes),
+		TaxNotes:     invoice.TaxNotes,
+	}
+	po.CalculateTotals()
+
+	gr := GoodsReceiptData{
+		ReceiptNumber: "GR-" + fake.Numerify("######"),
+		PONumber:      po.PONumber,
+		ReceivedDate:  po.DeliveryDate,
+		VendorInfo:    po.VendorInfo,
+		CustomerInfo:  po.CustomerInfo,
+		ShipTo:        po.ShipTo,
+	}
+	for _, item := range po.Items {
+		gr.Items = append(gr.Items, ReceivedItem{
+			Description: item.Description,
+			Unit:        item.Unit,
+			Ordered:     item.Quantity,
+			Received:    item.Quantity,
+		})
+	}
+
+	// vendors bill once the goods are delivered
+	invoice.PONumber = po.PONumber
+	invoice.InvoiceDate = addDays(gr.ReceivedDate, fake.IntBetween(0, 3))
+	invoice.DueDate = addDays(invoice.InvoiceDate, terms)
+
+	set := MatchSet{PurchaseOrder: po, GoodsReceipt: gr, Discrepancies: []Discrepancy{}}
+
+	for _, kind := range Discrepancies {
+		if !slices.Contains(discrepancies, kind) {
+			continue
+		}
+
+		line := fake.IntBetween(0, len(invoice.Items)-1)
+		switch kind {
+		case DiscrepancyQuantity:
+			item := &set.GoodsReceipt.Items[line]
+			item.Received = fake.Int64Between(0, item.Ordered-1)
+			set.Discrepancies = append(set.Discrepancies, Discrepancy{
+				Type:        kind,
+				Document:    "goodsReceipt",
+				Line:        line,
+				Description: item.Description,
+				Expected:    item.Ordered,
+				Actual:      item.Received,
+			})
+
+		case DiscrepancyPrice:
+			item := &invoice.Items[line]
+			increase := money.Rate(fake.IntBetween(1, 10) * 1000).Of(item.UnitPrice)
+			item.UnitPrice += max(increase, 1)
+			set.Discrepancies = append(set.Discrepancies, Discrepancy{
+				Type:        kind,
+				Document:    "invoice",
+				Line:        line,
+				Description: item.Description,
+				Expected:    po.Items[line].UnitPrice,
+				Actual:      item.UnitPrice,
+			})
+		}
+	}
+
+	invoice.CalculateTotals()
+	set.Invoice = invoice
+
+	return set, nil
+}
+
+// addDays moves an ISO 8601 date (2006-01-02) by days. Dates in any other
+// format are returned as they are.
+func addDays(date string, days int) string {
+	t, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return date
+	}
+
+	return t.AddDate(0, 0, days).Format(time.DateOnly)
+}
+
+// daysBetween counts the days from one ISO 8601 date to another, or the
+// usual 30 when either doesn't parse.
+func daysBetween(from, to string) int {
+	start, startErr := time.Parse(time.DateOnly, from)
+	end, endErr := time.Parse(time.DateOnly, to)
+	if startErr != nil || endErr != nil {
+		return 30
+	}
+
+	return int(end.Sub(start).Hours() / 24)
+}
+
+// CalculateTotals derives the item amounts and totals like
+// InvoiceData.CalculateTotals.
+func (d *PurchaseOrderData) CalculateTotals() {
+	d.Subtotal, d.DiscountTotal, d.TaxTotal = calculateTotals(d.Items, d.Taxes)
+	d.Total = d.Subtotal - d.DiscountTotal + d.TaxTotal
+}
+
+// ApplyTax replaces the order taxes with the ones the engine computes for the
+// vendor and customer jurisdictions, see InvoiceData.ApplyTax.
+func (d *PurchaseOrderData) ApplyTax(engine *tax.Engine) error {
+	return applyTax(d, engine)
+}
+
+func (d *PurchaseOrderData) taxFields() (vendor, customer tax.Jurisdiction, lines *[]tax.Line, notes *[]string) {
+	return d.VendorInfo.Jurisdiction(), d.CustomerInfo.Jurisdiction(), &d.Taxes, &d.TaxNotes
+}
+
+func (d *PurchaseOrderData) totals() totals {
+	return totals{d.Items, d.Subtotal, d.DiscountTotal, d.TaxTotal, d.Total}
+}
+
+// ValidatePurchaseOrder checks the purchase order data sent by a client
+// before it is rendered, like ValidateInvoice.
+func ValidatePurchaseOrder(v *validator.Validator, d *PurchaseOrderData) {
+	v.Struct(d)
+
+	orderDate, orderDateErr := time.Parse(time.DateOnly, d.OrderDate)
+	deliveryDate, deliveryDateErr := time.Parse(time.DateOnly, d.DeliveryDate)
+	if orderDateErr == nil && deliveryDateErr == nil {
+		v.CheckCode(!deliveryDate.Before(orderDate), "DeliveryDate", validator.CodeOutOfRange, "must not be before OrderDate")
+	}
+
+	validateItems(v, d.Items)
+	validateTaxes(v, d.Taxes)
+}
+
+// ValidatePurchaseOrderTotals checks the amounts a client sent against the
+// ones derived from its items, like ValidateTotals.
+func ValidatePurchaseOrderTotals(v *validator.Validator, sent, calculated *PurchaseOrderData) {
+	validateTotals(v, sent.totals(), calculated.totals())
+}
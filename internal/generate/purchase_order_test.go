@@ -0,0 +1,132 @@
+package generate
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/tax"
+)
+
+func TestGenerateMatchSet(t *testing.T) {
+	options := &GenerateInvoiceOptions{
+		Seed:          11,
+		NumberOfItems: 4,
+		InvoiceDate:   "2024-03-05",
+		DueDate:       "2024-04-19",
+		Currency:      "usd",
+		TaxEngine:     tax.NewEngine(),
+	}
+
+	set, err := GenerateMatchSet(options, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	po, gr, invoice := set.PurchaseOrder, set.GoodsReceipt, set.Invoice
+
+	assert.Equal(t, len(set.Discrepancies), 0)
+	assert.Equal(t, po.OrderDate, "2024-03-05")
+	assert.Equal(t, po.PaymentTerms, "Net 45")
+	assert.Equal(t, gr.PONumber, po.PONumber)
+	assert.Equal(t, invoice.PONumber, po.PONumber)
+	assert.Equal(t, gr.ReceivedDate, po.DeliveryDate)
+	assert.Equal(t, invoice.InvoiceDate >= gr.ReceivedDate, true)
+	assert.Equal(t, daysBetween(invoice.InvoiceDate, invoice.DueDate), 45)
+	assert.Equal(t, invoice.VendorInfo, po.VendorInfo)
+	assert.Equal(t, invoice.Total, po.Total)
+	for i, item := range gr.Items {
+		assert.Equal(t, item.Received, po.Items[i].Quantity)
+	}
+
+	order, err := GenerateRandomPurchaseOrderData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, fmt.Sprintf("%+v", order), fmt.Sprintf("%+v", po))
+
+	set, err = GenerateMatchSet(options, Discrepancies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(set.Discrepancies), 2)
+
+	quantity, price := set.Discrepancies[0], set.Discrepancies[1]
+	assert.Equal(t, quantity.Type, DiscrepancyQuantity)
+	assert.Equal(t, set.GoodsReceipt.Items[quantity.Line].Received, quantity.Actual)
+	assert.Equal(t, quantity.Actual < quantity.Expected, true)
+	assert.Equal(t, price.Type, DiscrepancyPrice)
+	assert.Equal(t, set.Invoice.Items[price.Line].UnitPrice, price.Actual)
+	assert.Equal(t, set.PurchaseOrder.Items[price.Line].UnitPrice, price.Expected)
+	assert.Equal(t, set.Invoice.Total > set.PurchaseOrder.Total, true)
+}
+
+func TestGenerateMatchSet_Discrepancies(t *testing.T) {
+	for _, kind := range Discrepancies {
+		t.Run(kind, func(t *testing.T) {
+			for seed := int64(1); seed <= 10; seed++ {
+				set, err := GenerateMatchSet(&GenerateInvoiceOptions{Seed: seed, NumberOfItems: 4, Currency: "usd"}, []string{kind})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.Equal(t, len(set.Discrepancies), 1)
+				planted := set.Discrepancies[0]
+				assert.Equal(t, planted.Type, kind)
+				assert.Equal(t, planted.Description, set.PurchaseOrder.Items[planted.Line].Description)
+
+				// the documents agree on every other line
+				for i, ordered := range set.PurchaseOrder.Items {
+					received, invoiced := set.GoodsReceipt.Items[i], set.Invoice.Items[i]
+					quantityMatches := received.Received == ordered.Quantity
+					priceMatches := invoiced.UnitPrice == ordered.UnitPrice
+
+					assert.Equal(t, quantityMatches, i != planted.Line || kind != DiscrepancyQuantity)
+					assert.Equal(t, priceMatches, i != planted.Line || kind != DiscrepancyPrice)
+					assert.Equal(t, invoiced.Quantity, ordered.Quantity)
+				}
+
+				switch kind {
+				case DiscrepancyQuantity:
+					assert.Equal(t, planted.Document, "goodsReceipt")
+					assert.Equal(t, planted.Actual < planted.Expected, true)
+					assert.Equal(t, set.Invoice.Total, set.PurchaseOrder.Total)
+				case DiscrepancyPrice:
+					assert.Equal(t, planted.Document, "invoice")
+					assert.Equal(t, planted.Actual > planted.Expected, true)
+					assert.Equal(t, set.Invoice.Total > set.PurchaseOrder.Total, true)
+				}
+			}
+		})
+	}
+}
+
+func TestGeneratePurchaseOrderHtml(t *testing.T) {
+	set, err := GenerateMatchSet(&GenerateInvoiceOptions{Seed: 3, InvoiceDate: "2024-03-05", DueDate: "2024-04-04", Currency: "usd"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderOptions := &RenderOptions{Language: language.English, Locale: language.English}
+
+	tests := []struct {
+		name     string
+		template string
+		data     any
+		contains []string
+	}{
+		{"Purchase order", PurchaseOrderTemplate, &set.PurchaseOrder, []string{"Purchase Order #", set.PurchaseOrder.PONumber, "March 5, 2024", "Net 30", set.PurchaseOrder.VendorInfo.Name}},
+		{"Goods receipt", GoodsReceiptTemplate, &set.GoodsReceipt, []string{"Goods Receipt #", set.GoodsReceipt.ReceiptNumber, set.GoodsReceipt.PONumber, "Ordered"}},
+		{"Invoice", InvoiceTemplate, &set.Invoice, []string{"PO #: <span data-field=\"po_number\">" + set.PurchaseOrder.PONumber}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := renderHtml(t, tt.template, tt.data, renderOptions)
+
+			for _, s := range tt.contains {
+				assert.Equal(t, strings.Contains(content, s), true)
+			}
+		})
+	}
+}
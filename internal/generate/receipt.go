@@ -0,0 +1,293 @@
+package generate
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+const (
+	TenderCash = "cash"
+	TenderCard = "card"
+)
+
+// TenderTypes lists the ways a receipt can be paid.
+var TenderTypes = []string{TenderCash, TenderCard}
+
+// GenerateReceiptOptions Seed drives every random choice of the generator, like
+// for invoices. A zero NumberOfItems and an empty Tender are drawn from the
+// seed. The json names are the query parameters of the fake receipt endpoint.
+type GenerateReceiptOptions struct {
+	Seed            int64       `json:"seed"`
+	MerchantName    string      `json:"merchantName" validate:"max=200"`
+	NumberOfItems   int         `json:"numberOfItems" validate:"min=1,max=30"`
+	Date            string      `json:"date" validate:"date"`
+	Currency        string      `json:"currency" validate:"currency"`
+	MerchantCountry string      `json:"merchantCountry" validate:"len=2"`
+	MerchantRegion  string      `json:"merchantRegion" validate:"max=3"`
+	Tender          string      `json:"tender" validate:"oneof=cash card"`
+	TaxEngine       *tax.Engine `json:"-"`
+}
+
+// Tender is how the purchase was paid. Card payments only ever carry the
+// masked card number (see MaskPAN) and are tendered for the exact total, so
+// Change is only given on cash.
+type Tender struct {
+	Type      string `validate:"required,oneof=cash card"`
+	CardBrand string `json:",omitempty" validate:"max=32"`
+	MaskedPAN string `json:",omitempty" validate:"max=32"`
+	AuthCode  string `json:",omitempty" validate:"max=16"`
+	Tendered  int64  `validate:"min=0"`
+	Change    int64
+}
+
+// ReceiptData is a point-of-sale receipt. Amounts follow the same rules as
+// InvoiceData: integer minor units, derived by CalculateTotals. Time is the
+// local time of the sale as HH:MM.
+type ReceiptData struct {
+	Merchant      CompanyInfo
+	TransactionID string        `validate:"required,max=64"`
+	Date          string        `validate:"required,date"`
+	Time          string        `validate:"max=5"`
+	Currency      string        `validate:"required,currency"`
+	Items         []InvoiceItem `validate:"required,dive"`
+	Subtotal      int64
+	DiscountTotal int64
+	Taxes         []tax.Line
+	TaxNotes      []string
+	TaxTotal      int64
+	Total         int64
+	Tender        Tender
+}
+
+const ReceiptTemplate = "receipt.tmpl"
+
+var cardBrands = []string{"Visa", "Mastercard", "American Express", "Discover"}
+
+// GenerateRandomReceiptData draws the receipt of the options. Like
+// GenerateRandomInvoiceData it fails when the merchant region can't be taxed.
+func GenerateRandomReceiptData(options *GenerateReceiptOptions) (ReceiptData, error) {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+
+	merchantName := options.MerchantName
+	if merchantName == "" {
+		merchantName = fake.Company().Name()
+	}
+	merchantCountry := strings.ToUpper(options.MerchantCountry)
+	if merchantCountry == "" {
+		merchantCountry = "US"
+	}
+	merchantRegion := strings.ToUpper(options.MerchantRegion)
+	if merchantRegion == "" && merchantCountry == "US" {
+		merchantRegion = fake.Address().StateAbbr()
+	}
+	// the store is in the state or country it collects tax for
+	street, city := partyAddress(fake, merchantCountry, merchantRegion)
+	merchantTaxID := ""
+	if merchantCountry != "US" {
+		merchantTaxID = merchantCountry + strconv.Itoa(fake.RandomNumber(9))
+	}
+
+	numberOfItems := options.NumberOfItems
+	if numberOfItems == 0 {
+		numberOfItems = fake.IntBetween(1, 12)
+	}
+
+	data := ReceiptData{
+		Merchant: CompanyInfo{
+			Name:          merchantName,
+			StreetAddress: street,
+			CityStateZip:  city,
+			Country:       merchantCountry,
+			Region:        merchantRegion,
+			TaxID:         merchantTaxID,
+		},
+		TransactionID: fake.Numerify("####-##-######"),
+		Date:          options.Date,
+		// stores open from 7 in the morning to 11 at night
+		Time:     fmt.Sprintf("%02d:%02d", fake.IntBetween(7, 22), fake.IntBetween(0, 59)),
+		Currency: strings.ToUpper(options.Currency),
+		Items:    generateReceiptItems(fake, numberOfItems),
+	}
+	data.CalculateTotals()
+
+	// jurisdictions without tax rules keep the randomly picked line rates
+	if options.TaxEngine != nil {
+		err := data.ApplyTax(options.TaxEngine)
+		if err != nil && !errors.Is(err, tax.ErrUnsupportedJurisdiction) {
+			return ReceiptData{}, err
+		}
+	}
+
+	data.Tender = generateTender(fake, options.Tender, data.Total, data.Currency)
+	data.CalculateTotals()
+
+	return data, nil
+}
+
+// generateReceiptItems draws store purchases: a handful of cheap goods,
+// mostly bought one at a time, all taxed at the same rate.
+func generateReceiptItems(fake faker.Faker, numOfItems int) []InvoiceItem {
+	items := []InvoiceItem{}
+	taxRate := taxRates[fake.IntBetween(0, len(taxRates)-1)]
+	for i := 0; i < numOfItems; i++ {
+		var description string
+		switch fake.IntBetween(0, 2) {
+		case 0:
+			description = fake.Food().Fruit()
+		case 1:
+			description = fake.Food().Vegetable()
+		default:
+			description = fake.Beer().Name()
+		}
+
+		item := InvoiceItem{
+			Description: description,
+			Quantity:    fake.Int64Between(1, 3),
+			// shelf prices end in 99 cents
+			UnitPrice: fake.Int64Between(1, 30)*100 - 1,
+			TaxRate:   taxRate,
+		}
+
+		// the odd line is on sale
+		if fake.IntBetween(0, 7) == 0 {
+			item.Discount = Discount{Type: DiscountPercentage, Rate: money.Rate(fake.IntBetween(1, 2) * 10000)}
+		}
+
+		items = append(items, item)
+	}
+	return items
+}
+
+// generateTender pays total with the given tender type, or a random one. Cash
+// is handed over as the exact amount or rounded up to a common bill.
+func generateTender(fake faker.Faker, tenderType string, total int64, currency string) Tender {
+	if tenderType == "" {
+		tenderType = fake.RandomStringElement(TenderTypes)
+	}
+
+	if tenderType == TenderCash {
+		return Tender{Type: TenderCash, Tendered: cashTendered(fake, total, currency)}
+	}
+
+	brand := fake.RandomStringElement(cardBrands)
+	pan := fake.Numerify("################")
+	if brand == "American Express" {
+		pan = fake.Numerify("###############")
+	}
+
+	return Tender{
+		Type:      TenderCard,
+		CardBrand: brand,
+		MaskedPAN: MaskPAN(pan),
+		AuthCode:  strings.ToUpper(fake.Bothify("??####")),
+		Tendered:  total,
+	}
+}
+
+// cashTendered picks the amount a customer hands over for total: the exact
+// change or the next multiple of one of the usual note values.
+func cashTendered(fake faker.Faker, total int64, currency string) int64 {
+	unit := int64(1)
+	for range money.Digits(currency) {
+		unit *= 10
+	}
+
+	candidates := []int64{total}
+	for _, note := range []int64{1, 5, 10, 20, 50, 100} {
+		step := note * unit
+		amount := (total + step - 1) / step * step
+		if amount != candidates[len(candidates)-1] {
+			candidates = append(candidates, amount)
+		}
+	}
+
+	// nobody pays a small bill with the largest note
+	return candidates[fake.IntBetween(0, min(3, len(candidates)-1))]
+}
+
+// MaskPAN hides every digit of a card number but the last four, keeping the
+// separators, so receipts never print a full card number. Masking an already
+// masked number leaves it as it is.
+func MaskPAN(pan string) string {
+	digits := 0
+	for _, r := range pan {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+
+	var s strings.Builder
+	for _, r := range pan {
+		if r >= '0' && r <= '9' {
+			digits--
+			if digits >= 4 {
+				r = '*'
+			}
+		}
+		s.WriteRune(r)
+	}
+	return s.String()
+}
+
+// CalculateTotals derives the item amounts and totals like
+// InvoiceData.CalculateTotals, then the tender: cards are charged the exact
+// total and cash gives back the change of the amount tendered, which
+// defaults to the total.
+func (d *ReceiptData) CalculateTotals() {
+	d.Subtotal, d.DiscountTotal, d.TaxTotal = calculateTotals(d.Items, d.Taxes)
+	d.Total = d.Subtotal - d.DiscountTotal + d.TaxTotal
+
+	if d.Tender.Type == TenderCard || d.Tender.Tendered == 0 {
+		d.Tender.Tendered = d.Total
+	}
+	d.Tender.Change = d.Tender.Tendered - d.Total
+}
+
+// ApplyTax replaces the receipt taxes with the ones the engine computes for a
+// sale inside the merchant jurisdiction, see InvoiceData.ApplyTax.
+func (d *ReceiptData) ApplyTax(engine *tax.Engine) error {
+	return applyTax(d, engine)
+}
+
+func (d *ReceiptData) taxFields() (vendor, customer tax.Jurisdiction, lines *[]tax.Line, notes *[]string) {
+	jurisdiction := d.Merchant.Jurisdiction()
+	return jurisdiction, jurisdiction, &d.Taxes, &d.TaxNotes
+}
+
+func (d *ReceiptData) totals() totals {
+	return totals{d.Items, d.Subtotal, d.DiscountTotal, d.TaxTotal, d.Total}
+}
+
+// ValidateReceipt checks the receipt data sent by a client before it is
+// rendered, like ValidateInvoice.
+func ValidateReceipt(v *validator.Validator, d *ReceiptData) {
+	v.Struct(d)
+
+	if d.Time != "" {
+		_, err := time.Parse("15:04", d.Time)
+		v.Check(err == nil, "Time", "must be in format HH:MM")
+	}
+
+	validateItems(v, d.Items)
+	validateTaxes(v, d.Taxes)
+}
+
+// ValidateReceiptTotals checks the amounts a client sent against the ones
+// derived from its items and tender, like ValidateTotals. The amount
+// tendered must cover the total.
+func ValidateReceiptTotals(v *validator.Validator, sent, calculated *ReceiptData) {
+	validateTotals(v, sent.totals(), calculated.totals())
+
+	tender := v.Field("Tender")
+	tender.CheckCode(calculated.Tender.Tendered >= calculated.Total, "Tendered", validator.CodeOutOfRange, fmt.Sprintf("must cover the Total (%d)", calculated.Total))
+	checkAmount(tender, "Change", sent.Tender.Change, calculated.Tender.Change)
+}
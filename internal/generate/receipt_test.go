@@ -0,0 +1,160 @@
+package generate
+
+import (
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/jaswdr/faker/v2"
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+func TestGenerateRandomReceiptData(t *testing.T) {
+	tests := []struct {
+		name    string
+		options GenerateReceiptOptions
+	}{
+		{"Cash", GenerateReceiptOptions{Seed: 1, Currency: "usd", Tender: TenderCash, TaxEngine: tax.NewEngine()}},
+		{"Card", GenerateReceiptOptions{Seed: 1, Currency: "usd", Tender: TenderCard, TaxEngine: tax.NewEngine()}},
+		{"Texas", GenerateReceiptOptions{Seed: 3, Currency: "usd", MerchantRegion: "tx", TaxEngine: tax.NewEngine()}},
+		{"Euro", GenerateReceiptOptions{Seed: 7, Currency: "eur", MerchantCountry: "DE", NumberOfItems: 20}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := GenerateRandomReceiptData(&tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, data.Tender.Tendered-data.Tender.Change, data.Total)
+			assert.Equal(t, data.Tender.Change >= 0, true)
+
+			if data.Tender.Type == TenderCard {
+				assert.Equal(t, data.Tender.Change, int64(0))
+				assert.Equal(t, strings.Count(data.Tender.MaskedPAN, "*") >= 11, true)
+			}
+
+			// the store is in the state or country it collects tax for
+			if merchant := data.Merchant; merchant.Country == "US" {
+				assert.Equal(t, strings.Contains(merchant.CityStateZip, ", "+merchant.Region+" "), true)
+			} else {
+				assert.Equal(t, regexp.MustCompile(`^\d{5} `).MatchString(merchant.CityStateZip), true)
+			}
+
+			again, err := GenerateRandomReceiptData(&tt.options)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, fmt.Sprintf("%+v", again), fmt.Sprintf("%+v", data))
+		})
+	}
+}
+
+func TestReceiptData_CalculateTotals(t *testing.T) {
+	tests := []struct {
+		name     string
+		tender   Tender
+		tendered int64
+		change   int64
+	}{
+		{"Cash with change", Tender{Type: TenderCash, Tendered: 500}, 500, 203},
+		{"Exact cash", Tender{Type: TenderCash}, 297, 0},
+		{"Card", Tender{Type: TenderCard, Tendered: 500}, 297, 0},
+		{"Short cash", Tender{Type: TenderCash, Tendered: 200}, 200, -97},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := ReceiptData{
+				Currency: "USD",
+				Items:    []InvoiceItem{{Description: "Apple", Quantity: 3, UnitPrice: 99}},
+				Tender:   tt.tender,
+			}
+			data.CalculateTotals()
+
+			assert.Equal(t, data.Total, int64(297))
+			assert.Equal(t, data.Tender.Tendered, tt.tendered)
+			assert.Equal(t, data.Tender.Change, tt.change)
+		})
+	}
+}
+
+func TestCashTendered(t *testing.T) {
+	fake := faker.NewWithSeed(rand.NewSource(1))
+
+	for range 100 {
+		tendered := cashTendered(fake, 1234, "USD")
+		switch tendered {
+		case 1234, 1300, 1500, 2000:
+		default:
+			t.Fatalf("unexpected amount tendered %d", tendered)
+		}
+	}
+}
+
+func TestMaskPAN(t *testing.T) {
+	tests := []struct {
+		pan      string
+		expected string
+	}{
+		{"4111111111111111", "************1111"},
+		{"4111 1111 1111 1111", "**** **** **** 1111"},
+		{"3782-822463-10005", "****-******-*0005"},
+		{"**** 1111", "**** 1111"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pan, func(t *testing.T) {
+			assert.Equal(t, MaskPAN(tt.pan), tt.expected)
+		})
+	}
+}
+
+func TestValidateReceiptTotals(t *testing.T) {
+	sent := ReceiptData{
+		Currency: "USD",
+		Items:    []InvoiceItem{{Description: "Apple", Quantity: 3, UnitPrice: 99}},
+		Tender:   Tender{Type: TenderCash, Tendered: 500, Change: 203},
+	}
+	calculated := sent
+	calculated.CalculateTotals()
+
+	v := validator.New()
+	ValidateReceiptTotals(v, &sent, &calculated)
+	assert.Equal(t, v.Valid(), true)
+
+	sent.Tender.Change = 1
+	calculated.Tender.Tendered = 200
+	calculated.CalculateTotals()
+	ValidateReceiptTotals(v, &sent, &calculated)
+	assert.Equal(t, len(v.Errors), 2)
+	assert.Equal(t, v.Errors["Tender.Tendered"][0].Code, validator.CodeOutOfRange)
+	assert.Equal(t, v.Errors["Tender.Change"][0].Code, validator.CodeMismatch)
+}
+
+func TestGenerateReceiptHtml(t *testing.T) {
+	data := ReceiptData{
+		Merchant:      CompanyInfo{Name: "Corner Market"},
+		TransactionID: "0001-02-000003",
+		Date:          "2024-03-05",
+		Time:          "09:30",
+		Currency:      "USD",
+		Items:         []InvoiceItem{{Description: "Apple", Quantity: 3, UnitPrice: 99}},
+		Tender:        Tender{Type: TenderCard, CardBrand: "Visa", MaskedPAN: MaskPAN("4111111111111111"), AuthCode: "AB1234"},
+	}
+	data.CalculateTotals()
+
+	content := renderHtml(t, ReceiptTemplate, &data, &RenderOptions{Language: language.English, Locale: language.English})
+
+	for _, s := range []string{"width: 72mm", "Corner Market", "March 5, 2024", "0001-02-000003", "3 @ $", "************1111", "AB1234"} {
+		assert.Equal(t, strings.Contains(content, s), true)
+	}
+	assert.Equal(t, strings.Contains(content, "4111111111111111"), false)
+}
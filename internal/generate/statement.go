@@ -0,0 +1,250 @@
+package generate
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+
+	"github.com/jaswdr/faker/v2"
+	"tools.lucasfaria.dev/internal/utils"
+)
+
+// GenerateStatementOptions Seed drives every random choice of the generator,
+// like for invoices. A zero AccountNumber or NumberOfTransactions is drawn
+// from the seed. The json names are the query parameters of the fake
+// statement endpoint.
+type GenerateStatementOptions struct {
+	Seed                 int64  `json:"seed"`
+	AccountHolder        string `json:"accountHolder" validate:"max=200"`
+	AccountNumber        int64  `json:"accountNumber" validate:"min=0"`
+	Country              string `json:"country" validate:"len=2"`
+	Currency             string `json:"currency" validate:"currency"`
+	PeriodStart          string `json:"from" validate:"date"`
+	PeriodEnd            string `json:"to" validate:"date"`
+	NumberOfTransactions int    `json:"numberOfTransactions" validate:"min=1,max=200"`
+	PayerName            string `json:"payerName" validate:"max=200"`
+
+	// Payments are credited to the account on top of the random
+	// transactions, each quoting the invoice it settles
+	Payments []StatementPayment `json:"-"`
+}
+
+// StatementPayment is an invoice paid into the account during the period. A
+// zero Amount is drawn from the seed.
+type StatementPayment struct {
+	InvoiceNumber string
+	Amount        int64
+}
+
+// StatementTransaction is a booking on the account. Credit and Debit are
+// positive amounts, at most one of them is set; Balance is the running
+// balance after the booking. InvoiceNumber is only set on the payments of
+// GenerateStatementOptions, whose Reference quotes it the way a payer
+// would.
+type StatementTransaction struct {
+	Date          string
+	Description   string
+	Reference     string `json:",omitempty"`
+	InvoiceNumber string `json:",omitempty"`
+	Credit        int64
+	Debit         int64
+	Balance       int64
+}
+
+// StatementData is a bank statement for one account over a period. Amounts
+// are integer minor units like on invoices; the balances and totals are
+// derived from the opening balance and the transactions by CalculateTotals.
+// The account number is only ever kept masked.
+type StatementData struct {
+	BankName       string
+	AccountHolder  CompanyInfo
+	AccountNumber  string
+	AccountDetails []InvoicePaymentDetails
+	Currency       string
+	PeriodStart    string
+	PeriodEnd      string
+	OpeningBalance int64
+	TotalCredits   int64
+	TotalDebits    int64
+	ClosingBalance int64
+	Transactions   []StatementTransaction
+}
+
+const StatementTemplate = "statement.tmpl"
+
+// transferLabels name the transfers of each rail the way banks abbreviate
+// them on statements.
+var transferLabels = map[string]string{
+	"ach":   "ACH",
+	"sepa":  "SEPA",
+	"swift": "WIRE",
+	"fps":   "FPS",
+	"eft":   "EFT",
+}
+
+// remittancePrefixes are the ways payers quote an invoice number in the
+// remittance information of a transfer.
+var remittancePrefixes = []string{"INV ", "INV-", "INVOICE ", "REF ", ""}
+
+func GenerateRandomStatementData(options *GenerateStatementOptions) StatementData {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+
+	holderName := options.AccountHolder
+	if holderName == "" {
+		holderName = fake.Company().Name()
+	}
+	country := strings.ToUpper(options.Country)
+	if country == "" {
+		country = "US"
+	}
+
+	holder := CompanyInfo{
+		Name:    holderName,
+		Email:   "finance@" + utils.TransformIntoValidEmailName(holderName) + ".com",
+		Country: country,
+	}
+	holder.StreetAddress, holder.CityStateZip = partyAddress(fake, country, "")
+
+	accountNumber := options.AccountNumber
+	if accountNumber == 0 {
+		// between 9 and 12 digits
+		accountNumber = fake.Int64Between(1e8, 1e12-1)
+	}
+
+	// the account is the one the holder would put on its invoices; PIX keys
+	// don't identify an account, so Brazilian holders get their SWIFT details
+	rail := defaultPaymentRails(country)[0]
+	if rail == "pix" {
+		rail = "swift"
+	}
+	method := getPaymentMethods(fake, []string{rail}, payee{holder, accountNumber, strings.ToUpper(options.Currency)})[0]
+
+	data := StatementData{
+		AccountHolder: holder,
+		Currency:      strings.ToUpper(options.Currency),
+		PeriodStart:   options.PeriodStart,
+		PeriodEnd:     options.PeriodEnd,
+		// between 1,000 and 50,000 units
+		OpeningBalance: fake.Int64Between(100000, 5000000),
+	}
+
+	for _, detail := range method.Details {
+		switch {
+		case detail.Name == "Bank name":
+			data.BankName = detail.Value
+		case detail.Name == "Account number" || detail.Name == "IBAN":
+			detail.Value = MaskPAN(detail.Value)
+			data.AccountNumber = detail.Value
+			data.AccountDetails = append(data.AccountDetails, detail)
+		case strings.HasPrefix(detail.Name, "Beneficiary"), strings.HasPrefix(detail.Name, "Intermediary"):
+			// the holder is printed on its own, correspondents don't
+			// concern the account
+		default:
+			data.AccountDetails = append(data.AccountDetails, detail)
+		}
+	}
+
+	numberOfTransactions := options.NumberOfTransactions
+	if numberOfTransactions == 0 {
+		numberOfTransactions = fake.IntBetween(8, 25)
+	}
+
+	days := daysBetween(options.PeriodStart, options.PeriodEnd)
+	transfer := transferLabels[rail]
+
+	for i := 0; i < numberOfTransactions; i++ {
+		transaction := randomTransaction(fake, transfer)
+		transaction.Date = addDays(options.PeriodStart, fake.IntBetween(0, days))
+		data.Transactions = append(data.Transactions, transaction)
+	}
+
+	payer := options.PayerName
+	if payer == "" {
+		payer = defaultCustomer.Name
+	}
+	for _, payment := range options.Payments {
+		amount := payment.Amount
+		if amount == 0 {
+			amount = fake.Int64Between(10000, 1000000)
+		}
+
+		data.Transactions = append(data.Transactions, StatementTransaction{
+			Date:          addDays(options.PeriodStart, fake.IntBetween(0, days)),
+			Description:   transfer + " CREDIT " + strings.ToUpper(payer),
+			Reference:     fake.RandomStringElement(remittancePrefixes) + payment.InvoiceNumber,
+			InvoiceNumber: payment.InvoiceNumber,
+			Credit:        amount,
+		})
+	}
+
+	// same-day bookings keep the order they were drawn in
+	slices.SortStableFunc(data.Transactions, func(a, b StatementTransaction) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+	data.CalculateTotals()
+
+	return data
+}
+
+// randomTransaction draws a booking of a small business account: mostly card
+// spend and outgoing transfers, with the odd deposit, incoming transfer or
+// bank fee.
+func randomTransaction(fake faker.Faker, transfer string) StatementTransaction {
+	counterparty := strings.ToUpper(fake.Company().Name())
+
+	switch fake.IntBetween(0, 9) {
+	case 0, 1, 2:
+		return StatementTransaction{
+			Description: "CARD PURCHASE " + counterparty,
+			Reference:   fake.Numerify("CARD ****####"),
+			Debit:       fake.Int64Between(500, 150000),
+		}
+	case 3, 4:
+		return StatementTransaction{
+			Description: transfer + " DEBIT " + counterparty,
+			Reference:   strings.ToUpper(fake.Bothify("??########")),
+			Debit:       fake.Int64Between(10000, 400000),
+		}
+	case 5:
+		return StatementTransaction{
+			Description: "PAYROLL " + transfer + " DEBIT",
+			Reference:   fake.Numerify("PR-######"),
+			Debit:       fake.Int64Between(200000, 800000),
+		}
+	case 6:
+		return StatementTransaction{
+			Description: "MONTHLY SERVICE FEE",
+			Debit:       fake.Int64Between(500, 3500),
+		}
+	case 7:
+		return StatementTransaction{
+			Description: "DEPOSIT",
+			Reference:   fake.Numerify("DEP ######"),
+			Credit:      fake.Int64Between(5000, 300000),
+		}
+	default:
+		return StatementTransaction{
+			Description: transfer + " CREDIT " + counterparty,
+			Reference:   strings.ToUpper(fake.Bothify("??########")),
+			Credit:      fake.Int64Between(10000, 500000),
+		}
+	}
+}
+
+// CalculateTotals derives the running balance of each transaction, the
+// credit and debit totals and the closing balance from the opening balance.
+func (d *StatementData) CalculateTotals() {
+	d.TotalCredits, d.TotalDebits = 0, 0
+
+	balance := d.OpeningBalance
+	for i := range d.Transactions {
+		t := &d.Transactions[i]
+		balance += t.Credit - t.Debit
+		t.Balance = balance
+
+		d.TotalCredits += t.Credit
+		d.TotalDebits += t.Debit
+	}
+
+	d.ClosingBalance = balance
+}
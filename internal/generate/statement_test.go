@@ -0,0 +1,121 @@
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+func TestGenerateRandomStatementData(t *testing.T) {
+	for _, country := range []string{"US", "DE", "GB", "BR"} {
+		t.Run(country, func(t *testing.T) {
+			options := &GenerateStatementOptions{
+				Seed:                 5,
+				AccountNumber:        123456789012,
+				Country:              country,
+				Currency:             "usd",
+				PeriodStart:          "2024-03-01",
+				PeriodEnd:            "2024-03-31",
+				NumberOfTransactions: 12,
+				Payments:             []StatementPayment{{InvoiceNumber: "10001", Amount: 125000}, {InvoiceNumber: "10002"}},
+			}
+
+			data := GenerateRandomStatementData(options)
+
+			assert.Equal(t, fmt.Sprintf("%+v", data), fmt.Sprintf("%+v", GenerateRandomStatementData(options)))
+			assert.Equal(t, len(data.Transactions), 14)
+			assert.Equal(t, data.AccountNumber != "", true)
+			assert.Equal(t, strings.Contains(data.AccountNumber, "*"), true)
+			assert.Equal(t, strings.Contains(fmt.Sprintf("%+v", data), "123456789012"), false)
+			// the holder lives in the country of the account
+			if holder := data.AccountHolder; country == "US" {
+				assert.Equal(t, regexp.MustCompile(`, [A-Z]{2} \d{5}$`).MatchString(holder.CityStateZip), true)
+			} else {
+				cities := postalFormats[country].cities
+				assert.Equal(t, slices.ContainsFunc(cities, func(city string) bool {
+					return strings.Contains(holder.CityStateZip, city)
+				}), true)
+			}
+
+			balance := data.OpeningBalance
+			paid := map[string]int64{}
+			for i, transaction := range data.Transactions {
+				assert.Equal(t, transaction.Date >= data.PeriodStart && transaction.Date <= data.PeriodEnd, true)
+				if i > 0 {
+					assert.Equal(t, transaction.Date >= data.Transactions[i-1].Date, true)
+				}
+				assert.Equal(t, (transaction.Credit == 0) != (transaction.Debit == 0), true)
+
+				balance += transaction.Credit - transaction.Debit
+				assert.Equal(t, transaction.Balance, balance)
+
+				if transaction.InvoiceNumber != "" {
+					assert.Equal(t, strings.HasSuffix(transaction.Reference, transaction.InvoiceNumber), true)
+					paid[transaction.InvoiceNumber] = transaction.Credit
+				}
+			}
+			assert.Equal(t, data.ClosingBalance, balance)
+			assert.Equal(t, data.ClosingBalance, data.OpeningBalance+data.TotalCredits-data.TotalDebits)
+			assert.Equal(t, paid["10001"], int64(125000))
+			assert.Equal(t, paid["10002"] > 0, true)
+		})
+	}
+}
+
+func TestStatementData_CalculateTotals(t *testing.T) {
+	data := StatementData{
+		OpeningBalance: 10000,
+		Transactions: []StatementTransaction{
+			{Description: "ACH CREDIT ACME CORP.", Credit: 25000},
+			{Description: "MONTHLY SERVICE FEE", Debit: 1500},
+			{Description: "WIRE OUT", Debit: 40000},
+			{Description: "ACH CREDIT INITECH", Credit: 7500},
+		},
+	}
+	data.CalculateTotals()
+
+	// every line carries the balance after it, which may go overdrawn
+	var balances []int64
+	for _, transaction := range data.Transactions {
+		balances = append(balances, transaction.Balance)
+	}
+	assert.Equal(t, fmt.Sprint(balances), "[35000 33500 -6500 1000]")
+	assert.Equal(t, data.TotalCredits, int64(32500))
+	assert.Equal(t, data.TotalDebits, int64(41500))
+	assert.Equal(t, data.ClosingBalance, int64(1000))
+
+	// recalculating after a change carries it to the following lines
+	data.Transactions[1].Debit = 500
+	data.CalculateTotals()
+	assert.Equal(t, data.Transactions[3].Balance, int64(2000))
+	assert.Equal(t, data.TotalDebits, int64(40500))
+}
+
+func TestGenerateStatementHtml(t *testing.T) {
+	data := StatementData{
+		BankName:       "First Bank",
+		AccountHolder:  CompanyInfo{Name: "Globex LLC", StreetAddress: "1 Main St", CityStateZip: "Springfield, IL 62701"},
+		AccountNumber:  MaskPAN("123456789"),
+		AccountDetails: []InvoicePaymentDetails{{Name: "Account number", Value: MaskPAN("123456789")}},
+		Currency:       "USD",
+		PeriodStart:    "2024-03-01",
+		PeriodEnd:      "2024-03-31",
+		OpeningBalance: 100000,
+		Transactions: []StatementTransaction{
+			{Date: "2024-03-05", Description: "ACH CREDIT ACME CORP.", Reference: "INV 10001", InvoiceNumber: "10001", Credit: 25000},
+			{Date: "2024-03-07", Description: "MONTHLY SERVICE FEE", Debit: 1500},
+		},
+	}
+	data.CalculateTotals()
+
+	content := renderHtml(t, StatementTemplate, &data, &RenderOptions{Language: language.English, Locale: language.English})
+
+	for _, s := range []string{"Account Statement", "Globex LLC", "*****6789", "March 1, 2024", "INV 10001", "1,000.00", "1,235.00"} {
+		assert.Equal(t, strings.Contains(content, s), true)
+	}
+}
@@ -0,0 +1,267 @@
+package generate
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jaswdr/faker/v2"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+const (
+	TaxFormW9     = "w9"
+	TaxFormW8BEN  = "w8ben"
+	TaxFormW8BENE = "w8bene"
+)
+
+// TaxForms lists the IRS forms vendors hand in when they are onboarded.
+var TaxForms = []string{TaxFormW9, TaxFormW8BEN, TaxFormW8BENE}
+
+// W9Data is IRS Form W-9, the taxpayer identification of a US vendor. The
+// entity type boxes of line 3 are TaxClassification, one of "C corporation",
+// "S corporation", "Partnership" or "LLC", the latter with LLCClassification
+// C, S or P.
+type W9Data struct {
+	Name              string
+	BusinessName      string `json:",omitempty"`
+	TaxClassification string
+	LLCClassification string `json:",omitempty"`
+	ExemptPayeeCode   string `json:",omitempty"`
+	StreetAddress     string
+	CityStateZip      string
+	EIN               string
+	SignatureDate     string
+}
+
+// W8BENData is IRS Form W-8BEN, the certificate of foreign status of a vendor
+// outside the US. Countries are printed by name; TreatyArticle is only set
+// when the vendor country has an income tax treaty with the US.
+type W8BENData struct {
+	Name                 string
+	CountryOfCitizenship string
+	StreetAddress        string
+	CityStateZip         string
+	Country              string
+	ForeignTaxID         string
+	DateOfBirth          string
+	TreatyCountry        string `json:",omitempty"`
+	TreatyArticle        string `json:",omitempty"`
+	TreatyRate           string `json:",omitempty"`
+	TreatyIncome         string `json:",omitempty"`
+	SignatureDate        string
+}
+
+// W8BENEData is IRS Form W-8BEN-E, the certificate of foreign status of a
+// vendor outside the US that is an entity. Chapter3Status is "Corporation"
+// or "Partnership"; vendors are active NFFEs for chapter 4, businesses whose
+// income doesn't come from investments. The form is signed by an officer of
+// the vendor, SignerName.
+type W8BENEData struct {
+	Name                      string
+	CountryOfIncorporation    string
+	Chapter3Status            string
+	Chapter4Status            string
+	StreetAddress             string
+	CityStateZip              string
+	Country                   string
+	ForeignTaxID              string
+	TreatyCountry             string `json:",omitempty"`
+	TreatyArticle             string `json:",omitempty"`
+	TreatyRate                string `json:",omitempty"`
+	TreatyIncome              string `json:",omitempty"`
+	TreatyLimitationOfBenefit string `json:",omitempty"`
+	SignerName                string
+	SignatureDate             string
+}
+
+const (
+	W9Template     = "w9.tmpl"
+	W8BENTemplate  = "w8ben.tmpl"
+	W8BENETemplate = "w8bene.tmpl"
+)
+
+// einPrefixes are the first two digits the IRS assigns EINs with; numbers
+// starting with anything else are rejected by TIN matching.
+var einPrefixes = []int{
+	1, 2, 3, 4, 5, 6, 10, 11, 12, 13, 14, 15, 16, 20, 21, 22, 23, 24, 25, 26,
+	27, 30, 31, 32, 33, 34, 35, 36, 37, 38, 39, 40, 41, 42, 43, 44, 45, 46, 47,
+	48, 50, 51, 52, 53, 54, 55, 56, 57, 58, 59, 60, 61, 62, 63, 64, 65, 66, 67,
+	68, 71, 72, 73, 74, 75, 76, 77, 80, 81, 82, 83, 84, 85, 86, 87, 88, 90, 91,
+	92, 93, 94, 95, 98, 99,
+}
+
+// treatyCountries have an income tax treaty with the US whose business
+// profits article (7) exempts services performed outside the US.
+var treatyCountries = []string{
+	"AT", "AU", "BE", "BG", "CA", "CH", "CN", "CY", "CZ", "DE", "DK", "EE",
+	"ES", "FI", "FR", "GB", "GR", "HU", "IE", "IL", "IN", "IS", "IT", "JP",
+	"KR", "LT", "LU", "LV", "MT", "MX", "NL", "NO", "NZ", "PL", "PT", "RO",
+	"SE", "SI", "SK", "TR", "ZA",
+}
+
+// fakeEIN draws an employer identification number (12-3456789) with one of
+// the prefixes the IRS issues.
+func fakeEIN(fake faker.Faker) string {
+	prefix := einPrefixes[fake.IntBetween(0, len(einPrefixes)-1)]
+	return fmt.Sprintf("%02d-%s", prefix, fake.Numerify("#######"))
+}
+
+// taxClassification picks the entity type a company of this name files as:
+// the legal form in the name when there is one, a partnership for names
+// made of partners, a corporation or LLC otherwise.
+func taxClassification(fake faker.Faker, name string) (classification, llc string) {
+	switch {
+	case strings.HasSuffix(name, " LLC"):
+		return "LLC", fake.RandomStringElement([]string{"C", "S", "P"})
+	case strings.HasSuffix(name, " Inc"), strings.HasSuffix(name, " Inc."):
+		return fake.RandomStringElement([]string{"C corporation", "S corporation"}), ""
+	case strings.Contains(name, " and "):
+		return "Partnership", ""
+	default:
+		classification = fake.RandomStringElement([]string{"C corporation", "S corporation", "LLC"})
+		if classification == "LLC" {
+			llc = fake.RandomStringElement([]string{"C", "S", "P"})
+		}
+		return classification, llc
+	}
+}
+
+// legalForms are the words company names end in or contain that make them
+// the name of an entity rather than of an individual, lower case and without
+// their dots.
+var legalForms = []string{
+	"ab", "ag", "as", "a/s", "bv", "co", "company", "corp", "corporation",
+	"gmbh", "group", "inc", "kg", "kk", "lda", "limited", "llc", "llp", "ltd",
+	"ltda", "nv", "oy", "plc", "pty", "sa", "sarl", "sas", "sl", "sons", "spa",
+	"srl",
+}
+
+// IndividualName reports whether name reads as the name of a person, the
+// only vendors that can file a W-8BEN: two to four words, none of them a
+// legal form, and not a partnership of several names. The company names
+// GenerateRandomInvoiceData draws are never individual names.
+func IndividualName(name string) bool {
+	words := strings.Fields(name)
+	if len(words) < 2 || len(words) > 4 {
+		return false
+	}
+
+	for _, word := range words {
+		word = strings.ToLower(strings.Trim(strings.ReplaceAll(word, ".", ""), ","))
+		if word == "and" || word == "&" || slices.Contains(legalForms, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// countryName prints an ISO 3166-1 alpha-2 code as the English country name.
+func countryName(code string) string {
+	region, err := language.ParseRegion(code)
+	if err != nil {
+		return code
+	}
+	return display.English.Regions().Name(region)
+}
+
+// GenerateRandomW9Data fills a W-9 for the vendor GenerateRandomInvoiceData
+// draws from the same options, signed on the invoice date.
+func GenerateRandomW9Data(options *GenerateInvoiceOptions) W9Data {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+	vendor := generateVendor(fake, options)
+
+	data := W9Data{
+		Name:          vendor.Name,
+		StreetAddress: vendor.StreetAddress,
+		CityStateZip:  vendor.CityStateZip,
+		EIN:           fakeEIN(fake),
+		SignatureDate: options.InvoiceDate,
+	}
+	data.TaxClassification, data.LLCClassification = taxClassification(fake, vendor.Name)
+
+	// corporations are exempt from backup withholding
+	if strings.HasSuffix(data.TaxClassification, "corporation") || data.LLCClassification == "C" || data.LLCClassification == "S" {
+		data.ExemptPayeeCode = "5"
+	}
+
+	return data
+}
+
+// treatyBenefits fills the treaty claim of a vendor in country, when the
+// country has a treaty with the US.
+func treatyBenefits(country string) (treatyCountry, article, rate, income string) {
+	if !slices.Contains(treatyCountries, country) {
+		return "", "", "", ""
+	}
+	return countryName(country), "7", "0%", "Business profits from services performed outside the United States"
+}
+
+// GenerateRandomW8BENData fills a W-8BEN for the vendor GenerateRandomInvoiceData
+// draws from the same options, signed on the invoice date. The form is filed
+// by individuals, so the vendor is a sole proprietor trading under their own
+// name, see IndividualName, from their address abroad. The foreign tax ID is
+// the personal one of the country, with valid check digits where foreignTINs
+// knows how to draw it.
+func GenerateRandomW8BENData(options *GenerateInvoiceOptions) W8BENData {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+	vendor := generateVendor(fake, options)
+
+	// adults of working age on the signature date
+	signed, err := time.Parse(time.DateOnly, options.InvoiceDate)
+	if err != nil {
+		signed = time.Now()
+	}
+	born := signed.AddDate(-fake.IntBetween(21, 70), 0, -fake.IntBetween(0, 364))
+
+	data := W8BENData{
+		Name:                 vendor.Name,
+		CountryOfCitizenship: countryName(vendor.Country),
+		StreetAddress:        vendor.StreetAddress,
+		CityStateZip:         vendor.CityStateZip,
+		Country:              countryName(vendor.Country),
+		ForeignTaxID:         fakeForeignTIN(fake, vendor.Country),
+		DateOfBirth:          born.Format(time.DateOnly),
+		SignatureDate:        options.InvoiceDate,
+	}
+
+	data.TreatyCountry, data.TreatyArticle, data.TreatyRate, data.TreatyIncome = treatyBenefits(vendor.Country)
+
+	return data
+}
+
+// GenerateRandomW8BENEData fills a W-8BEN-E for the vendor
+// GenerateRandomInvoiceData draws from the same options, signed on the
+// invoice date by one of its officers. The foreign tax ID is the one the
+// vendor prints on its invoices.
+func GenerateRandomW8BENEData(options *GenerateInvoiceOptions) W8BENEData {
+	fake := faker.NewWithSeed(rand.NewSource(options.Seed))
+	vendor := generateVendor(fake, options)
+
+	data := W8BENEData{
+		Name:                   vendor.Name,
+		CountryOfIncorporation: countryName(vendor.Country),
+		Chapter3Status:         "Corporation",
+		Chapter4Status:         "Active NFFE",
+		StreetAddress:          vendor.StreetAddress,
+		CityStateZip:           vendor.CityStateZip,
+		Country:                countryName(vendor.Country),
+		ForeignTaxID:           vendor.TaxID,
+		SignerName:             fake.Person().Name(),
+		SignatureDate:          options.InvoiceDate,
+	}
+	if classification, _ := taxClassification(fake, vendor.Name); classification == "Partnership" {
+		data.Chapter3Status = classification
+	}
+
+	data.TreatyCountry, data.TreatyArticle, data.TreatyRate, data.TreatyIncome = treatyBenefits(vendor.Country)
+	if data.TreatyCountry != "" {
+		// operating businesses pass the active trade or business test
+		data.TreatyLimitationOfBenefit = "Company with an item of income that meets active trade or business test"
+	}
+
+	return data
+}
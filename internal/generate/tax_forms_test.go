@@ -0,0 +1,209 @@
+package generate
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jaswdr/faker/v2"
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+// validEIN tells if ein is written 12-3456789 with a prefix the IRS issues.
+func validEIN(ein string) bool {
+	if len(ein) != 10 || ein[2] != '-' {
+		return false
+	}
+
+	prefix := 0
+	for i, r := range ein {
+		if i == 2 {
+			continue
+		}
+		if r < '0' || r > '9' {
+			return false
+		}
+		if i < 2 {
+			prefix = prefix*10 + int(r-'0')
+		}
+	}
+
+	return slices.Contains(einPrefixes, prefix)
+}
+
+func TestFakeEIN(t *testing.T) {
+	fake := faker.NewWithSeed(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		ein := fakeEIN(fake)
+		assert.Equal(t, validEIN(ein), true)
+	}
+
+	tests := []struct {
+		ein   string
+		valid bool
+	}{
+		{"12-3456789", true},
+		{"07-3456789", false},
+		{"00-3456789", false},
+		{"123456789", false},
+		{"12-345678X", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ein, func(t *testing.T) {
+			assert.Equal(t, validEIN(tt.ein), tt.valid)
+		})
+	}
+}
+
+func TestGenerateRandomW9Data(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		options := &GenerateInvoiceOptions{Seed: seed, Currency: "usd", InvoiceDate: "2024-03-05", DueDate: "2024-04-04"}
+
+		invoice, err := GenerateRandomInvoiceData(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w9 := GenerateRandomW9Data(options)
+
+		assert.Equal(t, w9.Name, invoice.VendorInfo.Name)
+		assert.Equal(t, w9.StreetAddress, invoice.VendorInfo.StreetAddress)
+		assert.Equal(t, w9.CityStateZip, invoice.VendorInfo.CityStateZip)
+		assert.Equal(t, strings.Contains(w9.CityStateZip, ", "+invoice.VendorInfo.Region+" "), true)
+		assert.Equal(t, validEIN(w9.EIN), true)
+		assert.Equal(t, w9.SignatureDate, "2024-03-05")
+
+		if strings.HasSuffix(w9.Name, " LLC") {
+			assert.Equal(t, w9.TaxClassification, "LLC")
+		}
+		assert.Equal(t, w9.LLCClassification != "", w9.TaxClassification == "LLC")
+	}
+}
+
+func TestGenerateRandomW8BENData(t *testing.T) {
+	options := &GenerateInvoiceOptions{Seed: 3, Currency: "eur", InvoiceDate: "2024-03-05", DueDate: "2024-04-04", VendorCountry: "DE", VendorName: "Anna Schmidt"}
+
+	invoice, err := GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w8ben := GenerateRandomW8BENData(options)
+
+	// the beneficial owner is the vendor itself
+	assert.Equal(t, w8ben.Name, invoice.VendorInfo.Name)
+	assert.Equal(t, w8ben.StreetAddress, invoice.VendorInfo.StreetAddress)
+	assert.Equal(t, w8ben.CityStateZip, invoice.VendorInfo.CityStateZip)
+	assert.Equal(t, slices.ContainsFunc(postalFormats["DE"].cities, func(city string) bool {
+		return strings.HasSuffix(w8ben.CityStateZip, " "+city)
+	}), true)
+	assert.Equal(t, validForeignTIN("DE", w8ben.ForeignTaxID), true)
+	assert.Equal(t, w8ben.Country, "Germany")
+	assert.Equal(t, w8ben.TreatyCountry, "Germany")
+	assert.Equal(t, w8ben.DateOfBirth < "2003-03-05", true)
+
+	options.VendorCountry = "BR"
+	assert.Equal(t, GenerateRandomW8BENData(options).TreatyArticle, "")
+}
+
+func TestGenerateRandomW8BENEData(t *testing.T) {
+	options := &GenerateInvoiceOptions{Seed: 3, Currency: "eur", InvoiceDate: "2024-03-05", DueDate: "2024-04-04", VendorCountry: "DE"}
+
+	invoice, err := GenerateRandomInvoiceData(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w8bene := GenerateRandomW8BENEData(options)
+
+	assert.Equal(t, w8bene.Name, invoice.VendorInfo.Name)
+	assert.Equal(t, w8bene.StreetAddress, invoice.VendorInfo.StreetAddress)
+	assert.Equal(t, w8bene.CityStateZip, invoice.VendorInfo.CityStateZip)
+	assert.Equal(t, w8bene.ForeignTaxID, invoice.VendorInfo.TaxID)
+	assert.Equal(t, w8bene.CountryOfIncorporation, "Germany")
+	assert.Equal(t, w8bene.Chapter4Status, "Active NFFE")
+	assert.Equal(t, w8bene.TreatyCountry, "Germany")
+	assert.Equal(t, w8bene.TreatyLimitationOfBenefit != "", true)
+	assert.Equal(t, w8bene.SignerName != w8bene.Name, true)
+
+	options.VendorName = "Becker, Wolf and Krause"
+	assert.Equal(t, GenerateRandomW8BENEData(options).Chapter3Status, "Partnership")
+
+	options.VendorCountry = "BR"
+	w8bene = GenerateRandomW8BENEData(options)
+	assert.Equal(t, w8bene.TreatyArticle, "")
+	assert.Equal(t, w8bene.TreatyLimitationOfBenefit, "")
+}
+
+func TestIndividualName(t *testing.T) {
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"Anna Schmidt", true},
+		{"Jean-Luc Picard", true},
+		{"María José García López", true},
+		{"Schmidt", false},
+		{"Schmidt-Becker", false},
+		{"Globex LLC", false},
+		{"Initech Ltd.", false},
+		{"Wolf and Sons", false},
+		{"Becker, Wolf and Krause", false},
+		{"Siemens AG", false},
+		{"Carrefour S.A.", false},
+		{"Smith & Jones", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, IndividualName(tt.name), tt.want)
+		})
+	}
+
+	// the vendors drawn at random are companies
+	for seed := int64(0); seed < 50; seed++ {
+		fake := faker.NewWithSeed(rand.NewSource(seed))
+		name := generateVendor(fake, &GenerateInvoiceOptions{VendorCountry: "DE"}).Name
+		assert.Equal(t, IndividualName(name), false)
+	}
+}
+
+func TestGenerateW9Html(t *testing.T) {
+	data := W9Data{
+		Name:              "Globex LLC",
+		TaxClassification: "LLC",
+		LLCClassification: "S",
+		ExemptPayeeCode:   "5",
+		StreetAddress:     "1 Main St",
+		CityStateZip:      "Springfield, IL 62701",
+		EIN:               "12-3456789",
+		SignatureDate:     "2024-03-05",
+	}
+
+	content := renderHtml(t, W9Template, &data, &RenderOptions{Language: language.AmericanEnglish, Locale: language.AmericanEnglish})
+
+	for _, s := range []string{"W-9", "Globex LLC", "Springfield, IL 62701", "12-3456789", "March 5, 2024"} {
+		assert.Equal(t, strings.Contains(content, s), true)
+	}
+}
+
+func TestGenerateW8BENEHtml(t *testing.T) {
+	data := W8BENEData{
+		Name:                   "Globex GmbH",
+		CountryOfIncorporation: "Germany",
+		Chapter3Status:         "Corporation",
+		Chapter4Status:         "Active NFFE",
+		StreetAddress:          "Hauptstraße 1",
+		CityStateZip:           "10115 Berlin",
+		Country:                "Germany",
+		ForeignTaxID:           "DE123456789",
+		SignerName:             "Anna Schmidt",
+		SignatureDate:          "2024-03-05",
+	}
+
+	content := renderHtml(t, W8BENETemplate, &data, &RenderOptions{Language: language.AmericanEnglish, Locale: language.AmericanEnglish})
+
+	for _, s := range []string{"W-8BEN-E", "Globex GmbH", "10115 Berlin", "DE123456789", "Active NFFE", "Anna Schmidt", "March 5, 2024"} {
+		assert.Equal(t, strings.Contains(content, s), true)
+	}
+}
@@ -0,0 +1,58 @@
+package generate
+
+import (
+	"tools.lucasfaria.dev/internal/tax"
+)
+
+func (c CompanyInfo) Jurisdiction() tax.Jurisdiction {
+	return tax.Jurisdiction{Country: c.Country, Region: c.Region, TaxID: c.TaxID}
+}
+
+// ApplyTax replaces the invoice taxes with the ones the engine computes for
+// the vendor and customer jurisdictions: every item gets the combined rate,
+// each tax is listed as a separate line and the totals are recalculated. On
+// error the invoice is left untouched.
+func (d *InvoiceData) ApplyTax(engine *tax.Engine) error {
+	return applyTax(d, engine)
+}
+
+func (d *InvoiceData) taxFields() (vendor, customer tax.Jurisdiction, lines *[]tax.Line, notes *[]string) {
+	return d.VendorInfo.Jurisdiction(), d.CustomerInfo.Jurisdiction(), &d.Taxes, &d.TaxNotes
+}
+
+// itemized is a document priced by its line items: an invoice, receipt,
+// purchase order or credit note.
+type itemized interface {
+	CalculateTotals()
+	totals() totals
+	// taxFields returns the jurisdictions the document is taxed between and
+	// the tax lines and notes ApplyTax replaces.
+	taxFields() (vendor, customer tax.Jurisdiction, lines *[]tax.Line, notes *[]string)
+}
+
+// applyTax is ApplyTax for every itemized document.
+func applyTax(d itemized, engine *tax.Engine) error {
+	d.CalculateTotals()
+
+	vendor, customer, lines, notes := d.taxFields()
+	t := d.totals()
+	// credit notes carry negative amounts, the engine taxes their magnitude
+	base := t.Subtotal - t.DiscountTotal
+	if base < 0 {
+		base = -base
+	}
+
+	result, err := engine.Calculate(vendor, customer, base)
+	if err != nil {
+		return err
+	}
+
+	for i := range t.Items {
+		t.Items[i].TaxRate = result.Rate
+	}
+	*lines = result.Lines
+	*notes = result.Notes
+	d.CalculateTotals()
+
+	return nil
+}
@@ -0,0 +1,105 @@
+package generate
+
+import (
+	"fmt"
+	"time"
+
+	"tools.lucasfaria.dev/internal/money"
+	"tools.lucasfaria.dev/internal/tax"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+// maxRate is 100%, the highest tax or discount rate accepted on a line.
+const maxRate = money.Rate(100000)
+
+// ValidateInvoice checks the invoice data sent by a client before it is
+// rendered: the field rules from the validate tags, then the ones spanning
+// several fields. Totals are not checked here, see ValidateTotals.
+func ValidateInvoice(v *validator.Validator, d *InvoiceData) {
+	v.Struct(d)
+
+	invoiceDate, invoiceDateErr := time.Parse(time.DateOnly, d.InvoiceDate)
+	dueDate, dueDateErr := time.Parse(time.DateOnly, d.DueDate)
+	if invoiceDateErr == nil && dueDateErr == nil {
+		v.CheckCode(!dueDate.Before(invoiceDate), "DueDate", validator.CodeOutOfRange, "must not be before InvoiceDate")
+	}
+
+	validateItems(v, d.Items)
+	validateTaxes(v, d.Taxes)
+}
+
+// validateItems checks the item rates the validate tags can't express, and
+// that the line amounts, alone and together, stay within money.MaxAmount so
+// deriving the totals can't overflow.
+func validateItems(v *validator.Validator, items []InvoiceItem) {
+	fields := v.Field("Items")
+	var gross int64
+	for i, item := range items {
+		iv := fields.Index(i)
+		iv.CheckCode(validator.Between(item.TaxRate, 0, maxRate), "TaxRate", validator.CodeOutOfRange, "must be between 0 and 100")
+		if item.Discount.Type == DiscountPercentage {
+			iv.Field("Discount").CheckCode(validator.Between(item.Discount.Rate, 0, maxRate), "Rate", validator.CodeOutOfRange, "must be between 0 and 100")
+		}
+
+		// the tags bound each factor; their product may not fit in int64, so
+		// it is checked by division
+		quantity := max(item.Quantity, 1)
+		if item.UnitPrice > money.MaxAmount/quantity {
+			iv.AddErrorCode("UnitPrice", validator.CodeOutOfRange, fmt.Sprintf("must keep Quantity × UnitPrice within %d", money.MaxAmount))
+			continue
+		}
+		gross = min(gross+quantity*item.UnitPrice, money.MaxAmount+1)
+	}
+	fields.CheckCode(gross <= money.MaxAmount, "", validator.CodeOutOfRange, fmt.Sprintf("must not add up to more than %d", money.MaxAmount))
+}
+
+// validateTaxes checks the tax lines a client sent. They are rendered as
+// they are whenever no engine rule replaces them.
+func validateTaxes(v *validator.Validator, taxes []tax.Line) {
+	fields := v.Field("Taxes")
+	for i, line := range taxes {
+		lv := fields.Index(i)
+		lv.CheckCode(validator.NotBlank(line.Name), "Name", validator.CodeRequired, "must be provided")
+		lv.CheckCode(validator.MaxChars(line.Name, 64), "Name", validator.CodeOutOfRange, "must not be more than 64 characters long")
+		lv.CheckCode(validator.Between(line.Rate, 0, maxRate), "Rate", validator.CodeOutOfRange, "must be between 0 and 100")
+	}
+}
+
+// ValidateTotals checks the amounts a client sent against the ones derived
+// from its items. Amounts left out (zero) are not checked, so clients may
+// send only the items and let CalculateTotals fill in the rest.
+func ValidateTotals(v *validator.Validator, sent, calculated *InvoiceData) {
+	validateTotals(v, sent.totals(), calculated.totals())
+}
+
+// totals are the derived amounts shared by every document made of line items.
+type totals struct {
+	Items         []InvoiceItem
+	Subtotal      int64
+	DiscountTotal int64
+	TaxTotal      int64
+	Total         int64
+}
+
+func (d *InvoiceData) totals() totals {
+	return totals{d.Items, d.Subtotal, d.DiscountTotal, d.TaxTotal, d.Total}
+}
+
+func checkAmount(v *validator.Validator, key string, sent, calculated int64) {
+	v.CheckCode(sent == 0 || sent == calculated, key, validator.CodeMismatch, fmt.Sprintf("must match the items (%d)", calculated))
+}
+
+func validateTotals(v *validator.Validator, sent, calculated totals) {
+	items := v.Field("Items")
+	for i := range min(len(sent.Items), len(calculated.Items)) {
+		item := items.Index(i)
+		checkAmount(item, "DiscountAmount", sent.Items[i].DiscountAmount, calculated.Items[i].DiscountAmount)
+		checkAmount(item, "TaxAmount", sent.Items[i].TaxAmount, calculated.Items[i].TaxAmount)
+		checkAmount(item, "LineTotal", sent.Items[i].LineTotal, calculated.Items[i].LineTotal)
+	}
+
+	checkAmount(v, "Subtotal", sent.Subtotal, calculated.Subtotal)
+	checkAmount(v, "DiscountTotal", sent.DiscountTotal, calculated.DiscountTotal)
+	checkAmount(v, "TaxTotal", sent.TaxTotal, calculated.TaxTotal)
+	checkAmount(v, "Total", sent.Total, calculated.Total)
+}
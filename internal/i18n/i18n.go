@@ -0,0 +1,117 @@
+// Package i18n holds the message catalogs used to render documents in other
+// languages, along with their text direction and date format.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed catalogs/*.json
+var catalogFS embed.FS
+
+type Catalog struct {
+	Tag        language.Tag
+	Direction  string            `json:"direction"`
+	DateFormat string            `json:"date_format"`
+	Months     [12]string        `json:"months"`
+	Messages   map[string]string `json:"messages"`
+}
+
+var (
+	catalogs = mustLoadCatalogs()
+	tags     = catalogTags()
+	matcher  = language.NewMatcher(tags)
+)
+
+func mustLoadCatalogs() map[language.Tag]*Catalog {
+	files, err := fs.Glob(catalogFS, "catalogs/*.json")
+	if err != nil {
+		panic(err)
+	}
+
+	loaded := make(map[language.Tag]*Catalog, len(files))
+	for _, file := range files {
+		data, err := catalogFS.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+
+		var c Catalog
+		if err := json.Unmarshal(data, &c); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", file, err))
+		}
+
+		c.Tag = language.MustParse(strings.TrimSuffix(strings.TrimPrefix(file, "catalogs/"), ".json"))
+		loaded[c.Tag] = &c
+	}
+
+	return loaded
+}
+
+// catalogTags lists the supported languages with English first, so it is
+// the fallback of the matcher.
+func catalogTags() []language.Tag {
+	list := []language.Tag{}
+	for tag := range catalogs {
+		if tag != language.English {
+			list = append(list, tag)
+		}
+	}
+	slices.SortFunc(list, func(a, b language.Tag) int {
+		return strings.Compare(a.String(), b.String())
+	})
+
+	return append([]language.Tag{language.English}, list...)
+}
+
+// Supported returns the tags of every available catalog.
+func Supported() []language.Tag {
+	return append([]language.Tag(nil), tags...)
+}
+
+// Match returns the catalog that best fits tag and whether the match is good
+// enough to be used; "pt" and "pt-PT" match pt-BR, "ja" matches nothing and
+// falls back to English.
+func Match(tag language.Tag) (*Catalog, bool) {
+	_, index, confidence := matcher.Match(tag)
+	return catalogs[tags[index]], confidence != language.No
+}
+
+// T translates a message key, falling back to English and then to the key
+// itself when a translation is missing.
+func (c *Catalog) T(key string) string {
+	if msg, ok := c.Messages[key]; ok {
+		return msg
+	}
+
+	if msg, ok := catalogs[language.English].Messages[key]; ok {
+		return msg
+	}
+
+	return key
+}
+
+func (c *Catalog) RTL() bool {
+	return c.Direction == "rtl"
+}
+
+// FormatDate renders a date with the catalog's month names and layout, e.g.
+// "2 de janeiro de 2006" in Portuguese.
+func (c *Catalog) FormatDate(t time.Time) string {
+	r := strings.NewReplacer(
+		"{day}", strconv.Itoa(t.Day()),
+		"{month}", c.Months[t.Month()-1],
+		"{year}", strconv.Itoa(t.Year()),
+	)
+
+	return r.Replace(c.DateFormat)
+}
@@ -0,0 +1,83 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+func TestCatalogsAreComplete(t *testing.T) {
+	english, _ := Match(language.English)
+
+	for _, tag := range Supported() {
+		c, ok := Match(tag)
+		assert.Equal(t, ok, true)
+
+		for key := range english.Messages {
+			if _, ok := c.Messages[key]; !ok {
+				t.Errorf("catalog %s is missing %q", tag, key)
+			}
+		}
+
+		for i, month := range c.Months {
+			if month == "" {
+				t.Errorf("catalog %s is missing month %d", tag, i+1)
+			}
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		tag       string
+		expected  language.Tag
+		supported bool
+		rtl       bool
+	}{
+		{"English", "en-US", language.English, true, false},
+		{"Brazilian Portuguese", "pt-BR", language.MustParse("pt-BR"), true, false},
+		{"Portuguese", "pt", language.MustParse("pt-BR"), true, false},
+		{"Arabic", "ar-SA", language.Arabic, true, true},
+		{"Hebrew", "he", language.Hebrew, true, true},
+		{"Unsupported", "ja", language.English, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := Match(language.MustParse(tt.tag))
+			assert.Equal(t, ok, tt.supported)
+			assert.Equal(t, c.Tag, tt.expected)
+			assert.Equal(t, c.RTL(), tt.rtl)
+		})
+	}
+}
+
+func TestCatalog_FormatDate(t *testing.T) {
+	date := time.Date(2024, time.March, 5, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		tag      string
+		expected string
+	}{
+		{"en", "March 5, 2024"},
+		{"pt-BR", "5 de março de 2024"},
+		{"de", "5. März 2024"},
+		{"fr", "5 mars 2024"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			c, _ := Match(language.MustParse(tt.tag))
+			assert.Equal(t, c.FormatDate(date), tt.expected)
+		})
+	}
+}
+
+func TestCatalog_T(t *testing.T) {
+	c, _ := Match(language.German)
+	assert.Equal(t, c.T("total"), "Gesamtbetrag")
+	assert.Equal(t, c.T("missing_key"), "missing_key")
+}
@@ -0,0 +1,215 @@
+// Package imaging post-processes rendered documents with the standard image
+// packages, for instance to make a clean screenshot look like a scan.
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"math"
+	"math/rand"
+	"slices"
+)
+
+// Profile describes how badly a document is degraded. Rotation is the
+// maximum skew in degrees (the actual angle is random, in either direction),
+// Blur the box blur radius in pixels, Noise the standard deviation of the
+// per-pixel noise and JPEGQuality, when not zero, the quality the image is
+// recompressed with to introduce compression artifacts.
+type Profile struct {
+	Name        string
+	Paper       bool
+	Rotation    float64
+	Blur        int
+	Noise       float64
+	JPEGQuality int
+}
+
+// Profiles are the degradation levels accepted by the degrade parameter, from
+// a clean office scanner to an old fax machine.
+var Profiles = []Profile{
+	{Name: "light", Paper: true, Rotation: 0.5, Noise: 4, JPEGQuality: 85},
+	{Name: "scan", Paper: true, Rotation: 1.5, Blur: 1, Noise: 8, JPEGQuality: 60},
+	{Name: "fax", Paper: true, Rotation: 3, Blur: 2, Noise: 18, JPEGQuality: 25},
+}
+
+// LookupProfile finds a profile by name.
+func LookupProfile(name string) (Profile, bool) {
+	i := slices.IndexFunc(Profiles, func(p Profile) bool { return p.Name == name })
+	if i < 0 {
+		return Profile{}, false
+	}
+	return Profiles[i], true
+}
+
+// ProfileNames lists the profile names, for validation messages.
+func ProfileNames() []string {
+	names := make([]string, len(Profiles))
+	for i, p := range Profiles {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// paperColor is the off-white of recycled office paper.
+var paperColor = color.RGBA{R: 246, G: 241, B: 228, A: 255}
+
+// Degrade applies the profile to img. All randomness comes from rng, so the
+// same seed produces the same "scan".
+func Degrade(img image.Image, profile Profile, rng *rand.Rand) (image.Image, error) {
+	out := toRGBA(img)
+
+	if profile.Paper {
+		applyPaper(out, rng)
+	}
+	if profile.Rotation > 0 {
+		angle := (rng.Float64()*2 - 1) * profile.Rotation
+		out = rotate(out, angle)
+	}
+	if profile.Blur > 0 {
+		out = boxBlur(out, profile.Blur)
+	}
+	if profile.Noise > 0 {
+		addNoise(out, profile.Noise, rng)
+	}
+	if profile.JPEGQuality > 0 {
+		return recompress(out, profile.JPEGQuality)
+	}
+
+	return out, nil
+}
+
+func toRGBA(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(out, out.Bounds(), img, b.Min, draw.Src)
+	return out
+}
+
+// applyPaper multiplies the page with the paper color, slightly uneven from
+// top to bottom the way a sheet catches the scanner light.
+func applyPaper(img *image.RGBA, rng *rand.Rand) {
+	b := img.Bounds()
+	phase := rng.Float64() * 2 * math.Pi
+
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		shade := 1 - 0.03*(1+math.Sin(phase+float64(y)/float64(b.Dy())*math.Pi))/2
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := img.PixOffset(x, y)
+			img.Pix[i+0] = uint8(float64(img.Pix[i+0]) * float64(paperColor.R) / 255 * shade)
+			img.Pix[i+1] = uint8(float64(img.Pix[i+1]) * float64(paperColor.G) / 255 * shade)
+			img.Pix[i+2] = uint8(float64(img.Pix[i+2]) * float64(paperColor.B) / 255 * shade)
+		}
+	}
+}
+
+// rotate turns the image by angle degrees around its center with bilinear
+// sampling, keeping the original size and filling the uncovered corners with
+// the paper color.
+func rotate(img *image.RGBA, angle float64) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+
+	rad := angle * math.Pi / 180
+	sin, cos := math.Sincos(rad)
+	cx, cy := float64(b.Dx())/2, float64(b.Dy())/2
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			dx, dy := float64(x)-cx, float64(y)-cy
+			sx := cos*dx + sin*dy + cx
+			sy := -sin*dx + cos*dy + cy
+			out.SetRGBA(x, y, bilinear(img, sx, sy))
+		}
+	}
+
+	return out
+}
+
+func bilinear(img *image.RGBA, x, y float64) color.RGBA {
+	b := img.Bounds()
+	x0, y0 := int(math.Floor(x)), int(math.Floor(y))
+	if x0 < 0 || y0 < 0 || x0+1 >= b.Dx() || y0+1 >= b.Dy() {
+		return paperColor
+	}
+
+	fx, fy := x-float64(x0), y-float64(y0)
+	var c [4]float64
+	for _, s := range []struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - fx) * (1 - fy)},
+		{x0 + 1, y0, fx * (1 - fy)},
+		{x0, y0 + 1, (1 - fx) * fy},
+		{x0 + 1, y0 + 1, fx * fy},
+	} {
+		i := img.PixOffset(s.x, s.y)
+		for k := range c {
+			c[k] += float64(img.Pix[i+k]) * s.w
+		}
+	}
+
+	return color.RGBA{R: uint8(c[0] + 0.5), G: uint8(c[1] + 0.5), B: uint8(c[2] + 0.5), A: uint8(c[3] + 0.5)}
+}
+
+// boxBlur runs a separable box blur of the given radius.
+func boxBlur(img *image.RGBA, radius int) *image.RGBA {
+	return blurPass(blurPass(img, radius, 1, 0), radius, 0, 1)
+}
+
+func blurPass(img *image.RGBA, radius, dx, dy int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			var sum [4]int
+			n := 0
+			for k := -radius; k <= radius; k++ {
+				sx, sy := x+k*dx, y+k*dy
+				if sx < 0 || sy < 0 || sx >= b.Dx() || sy >= b.Dy() {
+					continue
+				}
+				i := img.PixOffset(sx, sy)
+				for c := range sum {
+					sum[c] += int(img.Pix[i+c])
+				}
+				n++
+			}
+			i := out.PixOffset(x, y)
+			for c := range sum {
+				out.Pix[i+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+
+	return out
+}
+
+func addNoise(img *image.RGBA, stddev float64, rng *rand.Rand) {
+	for i := 0; i < len(img.Pix); i += 4 {
+		// the same offset on every channel gives grey grain instead of
+		// colored speckles
+		n := rng.NormFloat64() * stddev
+		for c := 0; c < 3; c++ {
+			img.Pix[i+c] = clamp(float64(img.Pix[i+c]) + n)
+		}
+	}
+}
+
+func clamp(f float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(f))))
+}
+
+// recompress round-trips the image through JPEG to bake in its artifacts.
+func recompress(img image.Image, quality int) (image.Image, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, err
+	}
+
+	return jpeg.Decode(&buf)
+}
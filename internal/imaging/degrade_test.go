@@ -0,0 +1,83 @@
+package imaging
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+func testPage() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 120, 80))
+	for y := 0; y < 80; y++ {
+		for x := 0; x < 120; x++ {
+			c := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+			if y%10 < 2 {
+				c = color.RGBA{A: 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDegrade(t *testing.T) {
+	for _, profile := range Profiles {
+		t.Run(profile.Name, func(t *testing.T) {
+			img, err := Degrade(testPage(), profile, rand.New(rand.NewSource(1)))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, img.Bounds().Dx(), 120)
+			assert.Equal(t, img.Bounds().Dy(), 80)
+		})
+	}
+}
+
+func TestDegradeIsSeeded(t *testing.T) {
+	profile, _ := LookupProfile("scan")
+
+	encode := func(seed int64) []byte {
+		img, err := Degrade(testPage(), profile, rand.New(rand.NewSource(seed)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+
+	assert.Equal(t, bytes.Equal(encode(42), encode(42)), true)
+	assert.Equal(t, bytes.Equal(encode(42), encode(43)), false)
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		format string
+		magic  []byte
+	}{
+		{"png", []byte("\x89PNG")},
+		{"jpeg", []byte{0xff, 0xd8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			content, err := Encode(testPage(), tt.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, bytes.HasPrefix(content, tt.magic), true)
+		})
+	}
+
+	_, err := Encode(testPage(), "gif")
+	assert.Equal(t, err != nil, true)
+}
@@ -0,0 +1,29 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// Encode writes img as "png" or "jpeg".
+func Encode(img image.Image, format string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	switch format {
+	case "png":
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	case "jpeg":
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("imaging: unsupported format %q", format)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+)
+
+// MaxLogoSize is the largest side of an embedded logo, in pixels. Logos are
+// printed at most 150px wide, so this leaves room for high density output.
+const MaxLogoSize = 512
+
+// MaxLogoPixels is the largest raster logo accepted, in pixels. Decoding
+// allocates every pixel, so a small file declaring huge dimensions could
+// otherwise exhaust memory.
+const MaxLogoPixels = 4096 * 4096
+
+var (
+	ErrUnsupportedLogo = errors.New("imaging: logo must be a PNG, JPEG or SVG image")
+	ErrLogoTooLarge    = errors.New("imaging: logo must not be larger than 4096×4096 pixels")
+)
+
+// LogoDataURI turns an uploaded logo into a data URI for the invoice
+// template. The type is sniffed from the content, whatever the client
+// claimed; raster logos larger than MaxLogoSize are scaled down, and ones
+// over MaxLogoPixels are rejected before they are decoded.
+func LogoDataURI(content []byte) (string, error) {
+	contentType := http.DetectContentType(content)
+
+	switch contentType {
+	case "image/png", "image/jpeg":
+		config, _, err := image.DecodeConfig(bytes.NewReader(content))
+		if err != nil {
+			return "", ErrUnsupportedLogo
+		}
+		if int64(config.Width)*int64(config.Height) > MaxLogoPixels {
+			return "", ErrLogoTooLarge
+		}
+
+		img, format, err := image.Decode(bytes.NewReader(content))
+		if err != nil {
+			return "", ErrUnsupportedLogo
+		}
+
+		b := img.Bounds()
+		if b.Dx() > MaxLogoSize || b.Dy() > MaxLogoSize {
+			content, err = Encode(Fit(img, MaxLogoSize), format)
+			if err != nil {
+				return "", err
+			}
+		}
+
+	default:
+		if !isSVG(content) {
+			return "", ErrUnsupportedLogo
+		}
+		// SVG scripts and external references are inert inside an <img>
+		contentType = "image/svg+xml"
+	}
+
+	return "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(content), nil
+}
+
+// isSVG reports whether content is an XML document with an svg root element.
+func isSVG(content []byte) bool {
+	dec := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start.Name.Local == "svg"
+		}
+	}
+}
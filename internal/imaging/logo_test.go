@@ -0,0 +1,84 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+func TestLogoDataURI(t *testing.T) {
+	var large bytes.Buffer
+	if err := png.Encode(&large, image.NewRGBA(image.Rect(0, 0, 1024, 256))); err != nil {
+		t.Fatal(err)
+	}
+
+	uri, err := LogoDataURI(large.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, ok := strings.CutPrefix(uri, "data:image/png;base64,")
+	assert.Equal(t, ok, true)
+	content, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := png.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, config.Width, MaxLogoSize)
+	assert.Equal(t, config.Height, MaxLogoSize/4)
+
+	svg := `<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><circle cx="5" cy="5" r="5"/></svg>`
+	uri, err = LogoDataURI([]byte(svg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, strings.HasPrefix(uri, "data:image/svg+xml;base64,"), true)
+
+	// only the header is needed to tell the logo is too large
+	var bomb bytes.Buffer
+	if err := png.Encode(&bomb, image.NewGray(image.Rect(0, 0, 1, 1))); err != nil {
+		t.Fatal(err)
+	}
+	header := bomb.Bytes()
+	binary.BigEndian.PutUint32(header[16:], 100000)
+	binary.BigEndian.PutUint32(header[20:], 100000)
+	binary.BigEndian.PutUint32(header[29:], crc32.ChecksumIEEE(header[12:29]))
+	_, err = LogoDataURI(header)
+	assert.Equal(t, err, ErrLogoTooLarge)
+
+	for _, content := range []string{"GIF89a", "<html><body></body></html>", ""} {
+		_, err = LogoDataURI([]byte(content))
+		assert.Equal(t, err, ErrUnsupportedLogo)
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		max           int
+		wantW, wantH  int
+	}{
+		{"Smaller", 100, 50, 512, 100, 50},
+		{"Wide", 2000, 500, 400, 400, 100},
+		{"Tall", 300, 900, 300, 100, 300},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := Fit(image.NewRGBA(image.Rect(0, 0, tt.width, tt.height)), tt.max)
+			assert.Equal(t, img.Bounds().Dx(), tt.wantW)
+			assert.Equal(t, img.Bounds().Dy(), tt.wantH)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package imaging
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Fit scales img down, keeping its aspect ratio, so that neither side is
+// larger than max pixels. Smaller images are returned as they are.
+func Fit(img image.Image, max int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= max && b.Dy() <= max {
+		return img
+	}
+
+	width, height := max, max
+	if b.Dx() > b.Dy() {
+		height = b.Dy() * max / b.Dx()
+	} else {
+		width = b.Dx() * max / b.Dy()
+	}
+
+	return Resize(img, width, height)
+}
+
+// Resize scales img to width x height pixels by averaging the source pixels
+// each destination pixel covers, which keeps downscaled text and edges
+// smooth.
+func Resize(img image.Image, width, height int) *image.RGBA {
+	src := toRGBA(img)
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	width, height = max(width, 1), max(height, 1)
+
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	if sw == 0 || sh == 0 {
+		return out
+	}
+	if sw == width && sh == height {
+		draw.Draw(out, out.Bounds(), src, image.Point{}, draw.Src)
+		return out
+	}
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					i := src.PixOffset(sx, sy)
+					for c := range sum {
+						sum[c] += int(src.Pix[i+c])
+					}
+				}
+			}
+
+			n := (y1 - y0) * (x1 - x0)
+			i := out.PixOffset(x, y)
+			for c := range sum {
+				out.Pix[i+c] = uint8(sum[c] / n)
+			}
+		}
+	}
+
+	return out
+}
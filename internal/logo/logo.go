@@ -0,0 +1,77 @@
+// Package logo draws monogram logos for fake companies. Logos are SVG
+// documents embedded as data URIs, so rendering an invoice never has to
+// reach the network, and the same name always gets the same logo.
+package logo
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"html"
+	"strings"
+	"unicode"
+)
+
+// palette holds background colors dark enough for white initials.
+var palette = []string{
+	"#0D8ABC", "#1B5E20", "#4A148C", "#B71C1C", "#E65100",
+	"#006064", "#37474F", "#880E4F", "#283593", "#5D4037",
+}
+
+// shapes are the SVG backgrounds behind the initials, on a 100x100 canvas.
+var shapes = []string{
+	`<circle cx="50" cy="50" r="50" fill="%s"/>`,
+	`<rect width="100" height="100" rx="20" fill="%s"/>`,
+	`<polygon points="50,0 93.3,25 93.3,75 50,100 6.7,75 6.7,25" fill="%s"/>`,
+	`<path d="M50 0 L95 15 V50 C95 75 75 92 50 100 C25 92 5 75 5 50 V15 Z" fill="%s"/>`,
+}
+
+// Initials takes the first letter or digit of the first two words of name,
+// upper cased. Names without any return "?".
+func Initials(name string) string {
+	var initials []rune
+
+	for _, word := range strings.Fields(name) {
+		for _, r := range word {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				initials = append(initials, unicode.ToUpper(r))
+				break
+			}
+		}
+		if len(initials) == 2 {
+			break
+		}
+	}
+
+	if len(initials) == 0 {
+		return "?"
+	}
+	return string(initials)
+}
+
+// SVG draws the logo of name. The color and shape are picked from a hash of
+// the name.
+func SVG(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	sum := h.Sum32()
+
+	color := palette[sum%uint32(len(palette))]
+	shape := fmt.Sprintf(shapes[(sum/uint32(len(palette)))%uint32(len(shapes))], color)
+
+	initials := Initials(name)
+	fontSize := 44
+	if len([]rune(initials)) == 1 {
+		fontSize = 56
+	}
+
+	return `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 100 100" width="100" height="100">` +
+		shape +
+		fmt.Sprintf(`<text x="50" y="50" dy="0.35em" text-anchor="middle" fill="#FFFFFF" font-family="Helvetica, Arial, sans-serif" font-weight="bold" font-size="%d">%s</text>`, fontSize, html.EscapeString(initials)) +
+		`</svg>`
+}
+
+// DataURI returns the logo of name as a data URI for an img src.
+func DataURI(name string) string {
+	return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(SVG(name)))
+}
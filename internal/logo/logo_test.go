@@ -0,0 +1,52 @@
+package logo
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+func TestInitials(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Acme Corp.", "AC"},
+		{"globex", "G"},
+		{"Schmidt & Söhne GmbH", "SS"},
+		{"  (Initech) Labs", "IL"},
+		{"3M Company", "3C"},
+		{"Élan Vital", "ÉV"},
+		{"!!!", "?"},
+		{"", "?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Initials(tt.name), tt.expected)
+		})
+	}
+}
+
+func TestSVG(t *testing.T) {
+	assert.Equal(t, SVG("Acme Corp."), SVG("Acme Corp."))
+	assert.Equal(t, SVG("Acme Corp.") == SVG("Globex"), false)
+	assert.Equal(t, strings.Contains(SVG("Acme Corp."), ">AC</text>"), true)
+	assert.Equal(t, strings.Contains(SVG("A&B Holdings"), ">AH</text>"), true)
+	assert.Equal(t, strings.Contains(SVG("<b> Tags"), "<b>"), false)
+}
+
+func TestDataURI(t *testing.T) {
+	uri := DataURI("Acme Corp.")
+
+	payload, ok := strings.CutPrefix(uri, "data:image/svg+xml;base64,")
+	assert.Equal(t, ok, true)
+
+	svg, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(svg), SVG("Acme Corp."))
+}
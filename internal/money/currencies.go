@@ -0,0 +1,74 @@
+package money
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Currency is an active ISO 4217 currency. Symbol is unambiguous across
+// currencies ("CA$"), NarrowSymbol is the one used locally ("$").
+type Currency struct {
+	Code         string `json:"code"`
+	NumericCode  string `json:"numeric_code"`
+	MinorUnits   int    `json:"minor_units"`
+	Name         string `json:"name"`
+	Symbol       string `json:"symbol"`
+	NarrowSymbol string `json:"narrow_symbol"`
+}
+
+//go:embed iso4217.csv
+var iso4217CSV []byte
+
+var (
+	currencies      = mustLoadCurrencies()
+	currenciesByISO = indexCurrencies(currencies)
+)
+
+func mustLoadCurrencies() []Currency {
+	records, err := csv.NewReader(bytes.NewReader(iso4217CSV)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("money: invalid iso4217.csv: %v", err))
+	}
+
+	list := make([]Currency, 0, len(records))
+	for _, record := range records[1:] {
+		minorUnits, err := strconv.Atoi(record[2])
+		if err != nil {
+			panic(fmt.Sprintf("money: invalid minor units for %s in iso4217.csv: %v", record[0], err))
+		}
+
+		list = append(list, Currency{
+			Code:         record[0],
+			NumericCode:  record[1],
+			MinorUnits:   minorUnits,
+			Name:         record[3],
+			Symbol:       record[4],
+			NarrowSymbol: record[5],
+		})
+	}
+
+	return list
+}
+
+func indexCurrencies(list []Currency) map[string]Currency {
+	index := make(map[string]Currency, len(list))
+	for _, c := range list {
+		index[c.Code] = c
+	}
+	return index
+}
+
+// Currencies returns every active ISO 4217 currency, sorted by code.
+func Currencies() []Currency {
+	return append([]Currency(nil), currencies...)
+}
+
+// LookupCurrency finds a currency by its alphabetic code, in any case.
+func LookupCurrency(code string) (Currency, bool) {
+	c, ok := currenciesByISO[strings.ToUpper(code)]
+	return c, ok
+}
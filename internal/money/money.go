@@ -0,0 +1,83 @@
+// Package money formats amounts that are always expressed in the minor unit
+// of their currency (cents for USD, for example), so totals can be derived
+// without float rounding errors.
+package money
+
+import (
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// Digits returns the number of minor-unit digits of a currency: 2 for USD,
+// 0 for JPY, 3 for KWD. Unknown currencies default to 2.
+func Digits(code string) int {
+	c, ok := LookupCurrency(code)
+	if !ok {
+		return 2
+	}
+
+	return c.MinorUnits
+}
+
+// Format renders an amount of minor units as a display string following the
+// CLDR conventions of locale, e.g. 123456 USD is "$1,234.56" in en-US, 123450
+// BRL is "R$ 1.234,50" in pt-BR and 105 EUR is "1,05 €" in de-DE. It should
+// only be called at render time.
+func Format(amount int64, code string, locale language.Tag) string {
+	sign, magnitude := "", uint64(amount)
+	if amount < 0 {
+		// negated unsigned, as -math.MinInt64 doesn't fit in an int64
+		sign, magnitude = "-", -magnitude
+	}
+
+	p := message.NewPrinter(locale)
+	digits := Digits(code)
+
+	// the major and minor units are printed as separate integers, so the
+	// amount is never converted to a float, not even for display
+	unit := uint64(1)
+	for range digits {
+		unit *= 10
+	}
+	value := p.Sprint(number.Decimal(magnitude / unit))
+	if digits > 0 {
+		value += decimalSeparator(p) + p.Sprint(number.Decimal(magnitude%unit, number.NoSeparator(), number.MinIntegerDigits(digits)))
+	}
+
+	return sign + lookupPattern(locale).place(symbol(code, p), value)
+}
+
+// decimalSeparator is the mark the printer puts between the major and minor
+// units, "." in en-US and "," in pt-BR.
+func decimalSeparator(p *message.Printer) string {
+	s := []rune(p.Sprint(number.Decimal(0, number.Scale(1))))
+	return string(s[1 : len(s)-1])
+}
+
+// symbol prefers the localized symbol from CLDR ("US$" in pt-BR) and falls
+// back to the registry for currencies CLDR doesn't know yet.
+func symbol(code string, p *message.Printer) string {
+	unit, err := currency.ParseISO(code)
+	if err == nil {
+		return p.Sprint(currency.Symbol(unit))
+	}
+
+	if c, ok := LookupCurrency(code); ok {
+		return c.Symbol
+	}
+
+	return strings.ToUpper(code)
+}
+
+// FormatRate renders a rate for display in locale, e.g. "8.25%" in en-US
+// and "8,25%" in pt-BR.
+func FormatRate(r Rate, locale language.Tag) string {
+	p := message.NewPrinter(locale)
+	value := float64(r) / rateScale
+
+	return p.Sprintf("%v%%", number.Decimal(value, number.MaxFractionDigits(3)))
+}
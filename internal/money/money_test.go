@@ -0,0 +1,115 @@
+package money
+
+import (
+	"math"
+	"testing"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/validator"
+)
+
+func TestDigits(t *testing.T) {
+	tests := []struct {
+		name     string
+		currency string
+		expected int
+	}{
+		{"Dollar", "USD", 2},
+		{"Lowercase code", "brl", 2},
+		{"Yen", "JPY", 0},
+		{"Won", "KRW", 0},
+		{"Kuwaiti dinar", "KWD", 3},
+		{"Unknown", "XYZ", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, Digits(tt.currency), tt.expected)
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		currency string
+		locale   string
+		expected string
+	}{
+		{"US dollars", 123456, "USD", "en-US", "$1,234.56"},
+		{"Brazilian real", 123450, "BRL", "pt-BR", "R$\u00a01.234,50"},
+		{"Yen has no minor unit", 1234, "JPY", "en-US", "¥1,234"},
+		{"Kuwaiti dinar has three", 123456, "KWD", "en-US", "KWD\u00a0123.456"},
+		{"Euro in Germany", 105, "EUR", "de-DE", "1,05\u00a0€"},
+		{"Euro in Austria", 105, "EUR", "de-AT", "€\u00a01,05"},
+		{"Euro in France", 123456, "EUR", "fr-FR", "1\u00a0234,56\u00a0€"},
+		{"Dollar in Mexico", 105, "USD", "es-MX", "USD\u00a01.05"},
+		{"Zero", 0, "GBP", "en-GB", "£0.00"},
+		{"Negative", -250, "USD", "en-US", "-$2.50"},
+		{"Negative in Germany", -250, "EUR", "de", "-2,50\u00a0€"},
+		{"Smallest amount", math.MinInt64, "JPY", "en-US", "-¥9,223,372,036,854,775,808"},
+		{"Beyond float precision", math.MaxInt64, "USD", "en-US", "$92,233,720,368,547,758.07"},
+		{"Unknown currency", 12345, "XYZ", "en-US", "XYZ\u00a0123.45"},
+		{"Root pattern", 105, "EUR", "und", "€\u00a01.05"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := Format(tt.amount, tt.currency, language.MustParse(tt.locale))
+			assert.Equal(t, actual, tt.expected)
+		})
+	}
+}
+
+func TestLookupCurrency(t *testing.T) {
+	tests := []struct {
+		name       string
+		code       string
+		found      bool
+		numeric    string
+		minorUnits int
+	}{
+		{"Dollar", "USD", true, "840", 2},
+		{"Lowercase code", "brl", true, "986", 2},
+		{"Yen", "JPY", true, "392", 0},
+		{"Bahraini dinar", "BHD", true, "048", 3},
+		{"Caribbean guilder", "XCG", true, "532", 2},
+		{"Unknown", "XYZ", false, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := LookupCurrency(tt.code)
+			assert.Equal(t, ok, tt.found)
+			assert.Equal(t, c.NumericCode, tt.numeric)
+			assert.Equal(t, c.MinorUnits, tt.minorUnits)
+		})
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+	codes := make(map[string]bool)
+	numerics := make(map[string]bool)
+
+	for _, c := range Currencies() {
+		assert.Equal(t, len(c.Code), 3)
+		assert.Equal(t, len(c.NumericCode), 3)
+		assert.Equal(t, c.Symbol != "" && c.NarrowSymbol != "", true)
+		assert.Equal(t, codes[c.Code], false)
+		assert.Equal(t, numerics[c.NumericCode], false)
+		codes[c.Code] = true
+		numerics[c.NumericCode] = true
+	}
+}
+
+func TestCurrencyRule(t *testing.T) {
+	for code, valid := range map[string]bool{"brl": true, "EUR": true, "BGN": false, "XYZ": false} {
+		v := validator.New()
+		v.Struct(struct {
+			Currency string `validate:"currency"`
+		}{code})
+		assert.Equal(t, v.Valid(), valid)
+	}
+}
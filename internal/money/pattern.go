@@ -0,0 +1,90 @@
+package money
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/language"
+)
+
+// nbsp separates a currency symbol from its number, so the two never wrap
+// onto different lines.
+const nbsp = "\u00a0"
+
+// currencyPattern is where the CLDR standard currency pattern of a locale
+// puts the symbol: "¤#,##0.00" in en, "¤ #,##0.00" in pt and "#,##0.00 ¤"
+// in de.
+type currencyPattern struct {
+	suffix bool // the symbol follows the number
+	spaced bool // a space separates the symbol from the number
+}
+
+// currencyPatterns are keyed by the locales that set their own pattern.
+// Other locales inherit the pattern of their CLDR parent, es-MX that of
+// es-419 and de-DE that of de, and the root pattern is "¤ #,##0.00".
+var currencyPatterns = map[string]currencyPattern{
+	"ar":     {suffix: true, spaced: true},
+	"cs":     {suffix: true, spaced: true},
+	"da":     {suffix: true, spaced: true},
+	"de":     {suffix: true, spaced: true},
+	"de-AT":  {spaced: true},
+	"de-CH":  {spaced: true},
+	"de-LI":  {spaced: true},
+	"el":     {suffix: true, spaced: true},
+	"en":     {},
+	"en-150": {suffix: true, spaced: true},
+	"es":     {suffix: true, spaced: true},
+	"es-419": {},
+	"fi":     {suffix: true, spaced: true},
+	"fr":     {suffix: true, spaced: true},
+	"he":     {suffix: true, spaced: true},
+	"hi":     {},
+	"hu":     {suffix: true, spaced: true},
+	"id":     {},
+	"it":     {suffix: true, spaced: true},
+	"it-CH":  {spaced: true},
+	"ja":     {},
+	"ko":     {},
+	"nl":     {spaced: true},
+	"pl":     {suffix: true, spaced: true},
+	"pt":     {spaced: true},
+	"pt-PT":  {suffix: true, spaced: true},
+	"ro":     {suffix: true, spaced: true},
+	"ru":     {suffix: true, spaced: true},
+	"sv":     {suffix: true, spaced: true},
+	"th":     {},
+	"tr":     {},
+	"zh":     {},
+}
+
+func lookupPattern(locale language.Tag) currencyPattern {
+	for t := locale; !t.IsRoot(); t = t.Parent() {
+		if pattern, ok := currencyPatterns[t.String()]; ok {
+			return pattern
+		}
+	}
+
+	return currencyPattern{spaced: true}
+}
+
+// place writes symbol on its side of value. Like CLDR currency spacing, a
+// symbol that ends in a letter, "KWD" or "CHF", is spaced from the number
+// even when the pattern isn't.
+func (c currencyPattern) place(symbol, value string) string {
+	var r rune
+	if c.suffix {
+		r, _ = utf8.DecodeRuneInString(symbol)
+	} else {
+		r, _ = utf8.DecodeLastRuneInString(symbol)
+	}
+
+	space := ""
+	if c.spaced || !unicode.IsSymbol(r) {
+		space = nbsp
+	}
+
+	if c.suffix {
+		return value + space + symbol
+	}
+	return symbol + space + value
+}
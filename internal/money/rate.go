@@ -0,0 +1,99 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rate is a percentage expressed in thousandths of a percent, so 8.25% is
+// 8250 and 6.875% is 6875. That is enough precision for every sales tax rate
+// in use while keeping all arithmetic in integers.
+//
+// In JSON a Rate is a plain percentage number such as 8.25, parsed exactly
+// without going through float64.
+type Rate int64
+
+const rateScale = 1000
+
+// MaxAmount is the largest amount of minor units accepted from clients. Of
+// multiplies amounts by the rate, so rates up to 100% applied to amounts
+// this size stay far within int64.
+const MaxAmount = 10_000_000_000_000
+
+var ErrInvalidRate = errors.New("invalid rate: must be a percentage with at most three decimal places")
+
+// ParseRate parses a percentage such as "8.25" or "6.875".
+func ParseRate(s string) (Rate, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > 3 || whole == "" || strings.HasPrefix(whole, "+") {
+		return 0, ErrInvalidRate
+	}
+
+	w, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidRate
+	}
+
+	var f int64
+	if frac != "" {
+		f, err = strconv.ParseInt(frac+strings.Repeat("0", 3-len(frac)), 10, 64)
+		if err != nil || f < 0 {
+			return 0, ErrInvalidRate
+		}
+	}
+
+	if strings.HasPrefix(whole, "-") {
+		return Rate(w*rateScale - f), nil
+	}
+
+	return Rate(w*rateScale + f), nil
+}
+
+// Of returns the rate applied to an amount of minor units. Halves are rounded
+// away from zero.
+func (r Rate) Of(amount int64) int64 {
+	product := amount * int64(r)
+	half := int64(100*rateScale) / 2
+	if product < 0 {
+		return -((-product + half) / (100 * rateScale))
+	}
+
+	return (product + half) / (100 * rateScale)
+}
+
+// RoundingError returns how far Of(amount) is above the exact product of the
+// rate and amount, in hundred-thousandths of a minor unit. It is never more
+// than half a unit either way.
+func (r Rate) RoundingError(amount int64) int64 {
+	return r.Of(amount)*100*rateScale - amount*int64(r)
+}
+
+// String renders the rate as a percentage number without trailing zeros,
+// e.g. "8.25" or "10".
+func (r Rate) String() string {
+	sign := ""
+	if r < 0 {
+		sign = "-"
+		r = -r
+	}
+
+	s := fmt.Sprintf("%s%d.%03d", sign, r/rateScale, r%rateScale)
+
+	return strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	rate, err := ParseRate(string(data))
+	if err != nil {
+		return err
+	}
+
+	*r = rate
+	return nil
+}
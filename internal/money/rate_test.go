@@ -0,0 +1,113 @@
+package money
+
+import (
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/text/language"
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Rate
+		valid    bool
+	}{
+		{"Whole", "10", 10000, true},
+		{"Two decimals", "8.25", 8250, true},
+		{"Three decimals", "6.875", 6875, true},
+		{"Zero", "0", 0, true},
+		{"Negative", "-2.5", -2500, true},
+		{"Too precise", "6.8751", 0, false},
+		{"Not a number", "abc", 0, false},
+		{"Empty", "", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseRate(tt.input)
+			assert.Equal(t, err == nil, tt.valid)
+			assert.Equal(t, actual, tt.expected)
+		})
+	}
+}
+
+func TestRate_Of(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     Rate
+		amount   int64
+		expected int64
+	}{
+		{"Whole percentage", 10000, 10000, 1000},
+		{"Rounds half up", 8250, 30030, 2477},
+		{"Rounds down", 5000, 1999, 100},
+		{"Three decimals", 6875, 10000, 688},
+		{"Negative amount", 8250, -30030, -2477},
+		{"Zero rate", 0, 12345, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.rate.Of(tt.amount), tt.expected)
+		})
+	}
+}
+
+func TestRate_RoundingError(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     Rate
+		amount   int64
+		expected int64
+	}{
+		{"Exact", 10000, 10000, 0},
+		{"Rounded down", 8250, 30030, -47500},
+		{"Rounded up", 5000, 1999, 5000},
+		{"Negative amount", 5000, -1999, -5000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.rate.RoundingError(tt.amount), tt.expected)
+		})
+	}
+}
+
+func TestFormatRate(t *testing.T) {
+	tests := []struct {
+		name     string
+		rate     Rate
+		locale   string
+		expected string
+	}{
+		{"Fractional", 8250, "en-US", "8.25%"},
+		{"Three decimals", 6875, "en-US", "6.875%"},
+		{"Whole", 10000, "en-US", "10%"},
+		{"Zero", 0, "en-US", "0%"},
+		{"Decimal comma", 8250, "pt-BR", "8,25%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, FormatRate(tt.rate, language.MustParse(tt.locale)), tt.expected)
+		})
+	}
+}
+
+func TestRate_JSON(t *testing.T) {
+	var input struct{ TaxRate Rate }
+	err := json.Unmarshal([]byte(`{"TaxRate": 8.25}`), &input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, input.TaxRate, Rate(8250))
+
+	js, err := json.Marshal(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(js), `{"TaxRate":8.25}`)
+}
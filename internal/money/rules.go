@@ -0,0 +1,15 @@
+package money
+
+import "tools.lucasfaria.dev/internal/validator"
+
+// Currency codes can be checked in validate tags, in any case.
+func init() {
+	validator.RegisterRule("currency", validator.Rule{
+		Check: func(code string) bool {
+			_, ok := LookupCurrency(code)
+			return ok
+		},
+		Code:    validator.CodeNotAllowed,
+		Message: "must be an active ISO 4217 currency code",
+	})
+}
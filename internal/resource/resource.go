@@ -0,0 +1,208 @@
+// Package resource decides which remote resources a rendered document may
+// reference. Documents are rendered by Chromium inside Gotenberg, which sits
+// on the internal network, so any URL a client puts in an invoice would
+// otherwise be fetched from there.
+package resource
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"tools.lucasfaria.dev/internal/imaging"
+)
+
+var (
+	ErrScheme           = errors.New("must be an http or https URL, or an embedded image")
+	ErrDomainNotAllowed = errors.New("must be hosted on an allowed domain")
+	ErrPrivateAddress   = errors.New("must not point to a private network address")
+	ErrTooLarge         = errors.New("is too large")
+	ErrFetch            = errors.New("could not be fetched")
+	ErrNotImage         = errors.New("must be a PNG, JPEG or SVG image")
+)
+
+// Policy is the resource policy of an environment. AllowedDomains match a
+// host and its subdomains; "*" allows every domain and an empty list none.
+// BlockPrivate refuses hosts resolving to loopback, private, link-local and
+// other non-public addresses. With Inline, allowed images are fetched here,
+// within MaxBytes and Timeout, and embedded as data URIs so that Chromium
+// never connects anywhere; without it, Chromium fetches the URL itself and
+// the address check can be bypassed by a host that re-resolves (DNS
+// rebinding) between the check and the render.
+type Policy struct {
+	AllowedDomains []string
+	BlockPrivate   bool
+	Inline         bool
+	MaxBytes       int64
+	Timeout        time.Duration
+}
+
+// Image applies the policy to the src of an image, returning the src to
+// render. Empty and embedded (data:image/...) sources are kept as they are.
+func (p *Policy) Image(ctx context.Context, src string) (string, error) {
+	if src == "" || strings.HasPrefix(src, "data:image/") {
+		return src, nil
+	}
+
+	u, err := p.checkURL(src)
+	if err != nil {
+		return "", err
+	}
+
+	if p.BlockPrivate {
+		if err := checkHost(ctx, u.Hostname()); err != nil {
+			return "", err
+		}
+	}
+
+	if !p.Inline {
+		return src, nil
+	}
+
+	return p.inline(ctx, u)
+}
+
+// checkURL checks the scheme and domain of src.
+func (p *Policy) checkURL(src string) (*url.URL, error) {
+	u, err := url.Parse(src)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, ErrScheme
+	}
+
+	if !p.allowedDomain(u.Hostname()) {
+		return nil, ErrDomainNotAllowed
+	}
+
+	return u, nil
+}
+
+func (p *Policy) allowedDomain(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	return slices.ContainsFunc(p.AllowedDomains, func(domain string) bool {
+		domain = strings.ToLower(domain)
+		return domain == "*" || host == domain || strings.HasSuffix(host, "."+domain)
+	})
+}
+
+// checkHost resolves host and refuses it if any of its addresses is not
+// public.
+func checkHost(ctx context.Context, host string) error {
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkAddr(addr)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return ErrFetch
+	}
+
+	for _, addr := range addrs {
+		if err := checkAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, not covered by
+// netip.Addr.IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+func checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() || sharedAddressSpace.Contains(addr) {
+		return ErrPrivateAddress
+	}
+
+	return nil
+}
+
+// inline fetches the image at u and returns it as a data URI.
+func (p *Policy) inline(ctx context.Context, u *url.URL) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return "", ErrFetch
+	}
+
+	resp, err := p.client().Do(req)
+	if err != nil {
+		// keep the policy errors of redirects and connections
+		for _, policyErr := range []error{ErrScheme, ErrDomainNotAllowed, ErrPrivateAddress} {
+			if errors.Is(err, policyErr) {
+				return "", policyErr
+			}
+		}
+		return "", ErrFetch
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", ErrFetch
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, p.MaxBytes+1))
+	if err != nil {
+		return "", ErrFetch
+	}
+	if int64(len(content)) > p.MaxBytes {
+		return "", ErrTooLarge
+	}
+
+	dataURI, err := imaging.LogoDataURI(content)
+	if errors.Is(err, imaging.ErrLogoTooLarge) {
+		return "", ErrTooLarge
+	}
+	if err != nil {
+		return "", ErrNotImage
+	}
+
+	return dataURI, nil
+}
+
+// client checks every redirect against the policy and, with BlockPrivate,
+// every address it connects to, which also covers hosts whose DNS answer
+// changed since checkHost.
+func (p *Policy) client() *http.Client {
+	dialer := &net.Dialer{Timeout: p.Timeout}
+	if p.BlockPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return ErrPrivateAddress
+			}
+			return checkAddr(addrPort.Addr())
+		}
+	}
+
+	return &http.Client{
+		Timeout: p.Timeout,
+		Transport: &http.Transport{
+			Proxy:               nil,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: p.Timeout,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return ErrFetch
+			}
+			_, err := p.checkURL(req.URL.String())
+			return err
+		},
+	}
+}
@@ -0,0 +1,143 @@
+package resource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+const testSVG = `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 10 10"><circle cx="5" cy="5" r="5"/></svg>`
+
+func TestPolicy_Image(t *testing.T) {
+	policy := &Policy{AllowedDomains: []string{"cdn.example.com", "10.1.2.3"}, BlockPrivate: true}
+
+	tests := []struct {
+		name string
+		src  string
+		want string
+		err  error
+	}{
+		{"Empty", "", "", nil},
+		{"Embedded image", "data:image/png;base64,AAAA", "data:image/png;base64,AAAA", nil},
+		{"Embedded HTML", "data:text/html,<script>", "", ErrScheme},
+		{"File", "file:///etc/passwd", "", ErrScheme},
+		{"Other domain", "https://metadata.google.internal/computeMetadata/v1/", "", ErrDomainNotAllowed},
+		{"Lookalike domain", "https://evilcdn.example.com/logo.png", "", ErrDomainNotAllowed},
+		{"Private address", "http://10.1.2.3/logo.png", "", ErrPrivateAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policy.Image(context.Background(), tt.src)
+			assert.Equal(t, err, tt.err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestPolicy_AllowedDomain(t *testing.T) {
+	policy := &Policy{AllowedDomains: []string{"example.com"}}
+
+	assert.Equal(t, policy.allowedDomain("example.com"), true)
+	assert.Equal(t, policy.allowedDomain("img.EXAMPLE.com."), true)
+	assert.Equal(t, policy.allowedDomain("example.com.evil.net"), false)
+	assert.Equal(t, (&Policy{AllowedDomains: []string{"*"}}).allowedDomain("anything.net"), true)
+	assert.Equal(t, (&Policy{}).allowedDomain("example.com"), false)
+}
+
+func TestCheckAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		private bool
+	}{
+		{"127.0.0.1", true},
+		{"10.0.0.1", true},
+		{"172.16.5.4", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::1", true},
+		{"fd00::1", true},
+		{"fe80::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"93.184.216.34", false},
+		{"2606:4700::1111", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			err := checkAddr(netip.MustParseAddr(tt.addr))
+			assert.Equal(t, err == ErrPrivateAddress, tt.private)
+		})
+	}
+}
+
+func TestPolicy_Inline(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/logo.svg", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testSVG))
+	})
+	mux.HandleFunc("/large.svg", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat(" ", 2048) + testSVG))
+	})
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html></html>"))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	})
+	mux.HandleFunc("/slow.svg", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(testSVG))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	// the test server listens on loopback, so only the connection check
+	// can be tested with BlockPrivate
+	policy := &Policy{AllowedDomains: []string{"127.0.0.1"}, Inline: true, MaxBytes: 1024, Timeout: 100 * time.Millisecond}
+
+	tests := []struct {
+		name string
+		path string
+		err  error
+	}{
+		{"Image", "/logo.svg", nil},
+		{"Too large", "/large.svg", ErrTooLarge},
+		{"Not an image", "/page.html", ErrNotImage},
+		{"Redirect elsewhere", "/redirect", ErrDomainNotAllowed},
+		{"Missing", "/missing.png", ErrFetch},
+		{"Timeout", "/slow.svg", ErrFetch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policy.Image(context.Background(), srv.URL+tt.path)
+			assert.Equal(t, err, tt.err)
+			if tt.err == nil {
+				assert.Equal(t, strings.HasPrefix(got, "data:image/svg+xml;base64,"), true)
+			}
+		})
+	}
+
+	policy.BlockPrivate = true
+	_, err := policy.inline(context.Background(), mustParseURL(t, srv.URL+"/logo.svg"))
+	assert.Equal(t, err, ErrPrivateAddress)
+}
+
+func mustParseURL(t *testing.T, s string) *url.URL {
+	t.Helper()
+	u, err := url.Parse(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u
+}
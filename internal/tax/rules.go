@@ -0,0 +1,110 @@
+package tax
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+
+	"tools.lucasfaria.dev/internal/money"
+)
+
+// Standard VAT rates of the EU member states, in thousandths of a percent.
+var euVATRates = map[string]money.Rate{
+	"AT": 20000, "BE": 21000, "BG": 20000, "CY": 19000, "CZ": 21000,
+	"DE": 19000, "DK": 25000, "EE": 24000, "ES": 21000, "FI": 25500,
+	"FR": 20000, "GR": 24000, "HR": 25000, "HU": 27000, "IE": 23000,
+	"IT": 22000, "LT": 21000, "LU": 17000, "LV": 21000, "MT": 18000,
+	"NL": 21000, "PL": 23000, "PT": 23000, "RO": 21000, "SE": 25000,
+	"SI": 22000, "SK": 23000,
+}
+
+const reverseChargeNote = "Reverse charge: VAT to be accounted for by the recipient (Article 196, Council Directive 2006/112/EC)"
+
+// euVAT charges the vendor's VAT on domestic sales, reverse-charges sales to
+// VAT-registered businesses in other member states, charges the customer's
+// VAT on cross-border consumer sales (OSS) and exempts exports.
+func euVAT(vendor, customer Jurisdiction, base int64) (Result, error) {
+	customerRate, customerInEU := euVATRates[customer.Country]
+
+	switch {
+	case customer.Country == vendor.Country:
+		return newResult(base, []Line{{Name: "VAT", Rate: euVATRates[vendor.Country]}}), nil
+	case customerInEU && customer.TaxID != "":
+		return newResult(base, []Line{{Name: "VAT", Rate: 0}}, reverseChargeNote), nil
+	case customerInEU:
+		return newResult(base, []Line{{Name: "VAT", Rate: customerRate}}), nil
+	default:
+		return newResult(base, []Line{{Name: "VAT", Rate: 0}}, "VAT exempt: export of services outside the EU"), nil
+	}
+}
+
+func australianGST(vendor, customer Jurisdiction, base int64) (Result, error) {
+	if customer.Country != "AU" {
+		return newResult(base, []Line{{Name: "GST", Rate: 0}}, "GST-free export"), nil
+	}
+
+	return newResult(base, []Line{{Name: "GST", Rate: 10000}}), nil
+}
+
+func newZealandGST(vendor, customer Jurisdiction, base int64) (Result, error) {
+	if customer.Country != "NZ" {
+		return newResult(base, []Line{{Name: "GST", Rate: 0}}, "Zero-rated export"), nil
+	}
+
+	return newResult(base, []Line{{Name: "GST", Rate: 15000}}), nil
+}
+
+// indianGST splits the standard 18% rate into CGST and SGST for sales within
+// the vendor's state and charges IGST on sales to other states.
+func indianGST(vendor, customer Jurisdiction, base int64) (Result, error) {
+	switch {
+	case customer.Country != "IN":
+		return newResult(base, []Line{{Name: "IGST", Rate: 0}}, "Supply meant for export under LUT without payment of IGST"), nil
+	case vendor.Region == "" || customer.Region == "":
+		return Result{}, fmt.Errorf("%w IN: both parties need a state code to split CGST/SGST from IGST", ErrUnknownRegion)
+	case vendor.Region == customer.Region:
+		return newResult(base, []Line{{Name: "CGST", Rate: 9000}, {Name: "SGST", Rate: 9000}}), nil
+	default:
+		return newResult(base, []Line{{Name: "IGST", Rate: 18000}}), nil
+	}
+}
+
+//go:embed us_sales_tax.csv
+var usSalesTaxCSV []byte
+
+// usSalesTaxRates maps each state code to its state-level sales tax rate.
+var usSalesTaxRates = mustLoadUSSalesTaxRates()
+
+func mustLoadUSSalesTaxRates() map[string]money.Rate {
+	records, err := csv.NewReader(bytes.NewReader(usSalesTaxCSV)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("tax: invalid us_sales_tax.csv: %v", err))
+	}
+
+	rates := make(map[string]money.Rate, len(records))
+	for _, record := range records[1:] {
+		rate, err := money.ParseRate(record[2])
+		if err != nil {
+			panic(fmt.Sprintf("tax: invalid rate for %s in us_sales_tax.csv: %v", record[0], err))
+		}
+		rates[record[0]] = rate
+	}
+
+	return rates
+}
+
+// usSalesTax charges the state sales tax of the customer's state, since
+// sales tax is destination based. Sales to customers abroad carry no tax.
+func usSalesTax(vendor, customer Jurisdiction, base int64) (Result, error) {
+	if customer.Country != "US" {
+		return Result{}, nil
+	}
+
+	rate, ok := usSalesTaxRates[customer.Region]
+	if !ok {
+		return Result{}, fmt.Errorf("%w US: %q is not a state code", ErrUnknownRegion, customer.Region)
+	}
+
+	return newResult(base, []Line{{Name: customer.Region + " sales tax", Rate: rate}}), nil
+}
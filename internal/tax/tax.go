@@ -0,0 +1,113 @@
+// Package tax computes the taxes due on an invoice from the jurisdictions of
+// the vendor and the customer. Rules are registered per vendor country, so
+// new jurisdictions can be plugged into an Engine without touching callers.
+package tax
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"tools.lucasfaria.dev/internal/money"
+)
+
+var (
+	ErrUnsupportedJurisdiction = errors.New("no tax rules for jurisdiction")
+	ErrUnknownRegion           = errors.New("unknown region for jurisdiction")
+)
+
+// Jurisdiction identifies where a party is established for tax purposes.
+// Country is an ISO 3166-1 alpha-2 code and Region a state, province or
+// territory code within it (e.g. "CA" in the US, "KA" in India). TaxID is the
+// VAT/GST registration number, used to tell business from consumer sales.
+type Jurisdiction struct {
+	Country string
+	Region  string
+	TaxID   string
+}
+
+// Line is a single tax charged on the invoice, such as "VAT" or "CGST".
+type Line struct {
+	Name   string
+	Rate   money.Rate
+	Amount int64
+}
+
+// Result holds the tax lines that apply to a taxable base, the combined Rate
+// of all of them, and any notes that must be printed on the invoice (for
+// instance a reverse-charge statement).
+type Result struct {
+	Rate  money.Rate
+	Lines []Line
+	Notes []string
+}
+
+// Rule computes the taxes due on a sale made by a vendor established in the
+// country the rule is registered for.
+type Rule interface {
+	Calculate(vendor, customer Jurisdiction, base int64) (Result, error)
+}
+
+// RuleFunc adapts an ordinary function to the Rule interface.
+type RuleFunc func(vendor, customer Jurisdiction, base int64) (Result, error)
+
+func (f RuleFunc) Calculate(vendor, customer Jurisdiction, base int64) (Result, error) {
+	return f(vendor, customer, base)
+}
+
+type Engine struct {
+	rules map[string]Rule
+}
+
+// NewEngine returns an engine with the built-in rules registered: EU VAT,
+// Australian and New Zealand GST, Indian GST and US state sales tax.
+func NewEngine() *Engine {
+	e := &Engine{rules: make(map[string]Rule)}
+
+	for country := range euVATRates {
+		e.Register(country, RuleFunc(euVAT))
+	}
+	e.Register("AU", RuleFunc(australianGST))
+	e.Register("NZ", RuleFunc(newZealandGST))
+	e.Register("IN", RuleFunc(indianGST))
+	e.Register("US", RuleFunc(usSalesTax))
+
+	return e
+}
+
+// Register sets the rule for vendors established in country, replacing any
+// rule previously registered for it.
+func (e *Engine) Register(country string, rule Rule) {
+	e.rules[strings.ToUpper(country)] = rule
+}
+
+// Calculate returns the taxes due on base, an amount in minor units.
+func (e *Engine) Calculate(vendor, customer Jurisdiction, base int64) (Result, error) {
+	vendor = normalize(vendor)
+	customer = normalize(customer)
+
+	rule, ok := e.rules[vendor.Country]
+	if !ok {
+		return Result{}, fmt.Errorf("%w %q", ErrUnsupportedJurisdiction, vendor.Country)
+	}
+
+	return rule.Calculate(vendor, customer, base)
+}
+
+func normalize(j Jurisdiction) Jurisdiction {
+	j.Country = strings.ToUpper(strings.TrimSpace(j.Country))
+	j.Region = strings.ToUpper(strings.TrimSpace(j.Region))
+	j.TaxID = strings.TrimSpace(j.TaxID)
+	return j
+}
+
+// newResult builds a Result from the given lines, computing each line amount
+// on the base and the combined rate.
+func newResult(base int64, lines []Line, notes ...string) Result {
+	result := Result{Lines: lines, Notes: notes}
+	for i := range result.Lines {
+		result.Lines[i].Amount = result.Lines[i].Rate.Of(base)
+		result.Rate += result.Lines[i].Rate
+	}
+	return result
+}
@@ -0,0 +1,77 @@
+package tax
+
+import (
+	"errors"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+	"tools.lucasfaria.dev/internal/money"
+)
+
+func TestEngine_Calculate(t *testing.T) {
+	tests := []struct {
+		name     string
+		vendor   Jurisdiction
+		customer Jurisdiction
+		lines    []Line
+		notes    int
+	}{
+		{"EU domestic", Jurisdiction{Country: "DE"}, Jurisdiction{Country: "de"}, []Line{{"VAT", 19000, 1900}}, 0},
+		{"EU reverse charge", Jurisdiction{Country: "DE"}, Jurisdiction{Country: "FR", TaxID: "FR40303265045"}, []Line{{"VAT", 0, 0}}, 1},
+		{"EU cross-border consumer", Jurisdiction{Country: "DE"}, Jurisdiction{Country: "FR"}, []Line{{"VAT", 20000, 2000}}, 0},
+		{"EU export", Jurisdiction{Country: "NL"}, Jurisdiction{Country: "US", Region: "CA"}, []Line{{"VAT", 0, 0}}, 1},
+		{"AU domestic", Jurisdiction{Country: "AU"}, Jurisdiction{Country: "AU"}, []Line{{"GST", 10000, 1000}}, 0},
+		{"NZ domestic", Jurisdiction{Country: "NZ"}, Jurisdiction{Country: "NZ"}, []Line{{"GST", 15000, 1500}}, 0},
+		{"NZ export", Jurisdiction{Country: "NZ"}, Jurisdiction{Country: "AU"}, []Line{{"GST", 0, 0}}, 1},
+		{"IN intra-state", Jurisdiction{Country: "IN", Region: "KA"}, Jurisdiction{Country: "IN", Region: "KA"}, []Line{{"CGST", 9000, 900}, {"SGST", 9000, 900}}, 0},
+		{"IN inter-state", Jurisdiction{Country: "IN", Region: "KA"}, Jurisdiction{Country: "IN", Region: "MH"}, []Line{{"IGST", 18000, 1800}}, 0},
+		{"US sales tax", Jurisdiction{Country: "US", Region: "NY"}, Jurisdiction{Country: "US", Region: "MN"}, []Line{{"MN sales tax", 6875, 688}}, 0},
+		{"US no sales tax state", Jurisdiction{Country: "US", Region: "CA"}, Jurisdiction{Country: "US", Region: "OR"}, []Line{{"OR sales tax", 0, 0}}, 0},
+		{"US export", Jurisdiction{Country: "US", Region: "CA"}, Jurisdiction{Country: "DE"}, nil, 0},
+	}
+
+	e := NewEngine()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := e.Calculate(tt.vendor, tt.customer, 10000)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, len(result.Lines), len(tt.lines))
+			var rate money.Rate
+			for i, line := range tt.lines {
+				assert.Equal(t, result.Lines[i], line)
+				rate += line.Rate
+			}
+			assert.Equal(t, result.Rate, rate)
+			assert.Equal(t, len(result.Notes), tt.notes)
+		})
+	}
+}
+
+func TestEngine_CalculateErrors(t *testing.T) {
+	e := NewEngine()
+
+	_, err := e.Calculate(Jurisdiction{Country: "BR"}, Jurisdiction{Country: "BR"}, 10000)
+	assert.Equal(t, errors.Is(err, ErrUnsupportedJurisdiction), true)
+
+	_, err = e.Calculate(Jurisdiction{Country: "US"}, Jurisdiction{Country: "US", Region: "XX"}, 10000)
+	assert.Equal(t, errors.Is(err, ErrUnknownRegion), true)
+
+	_, err = e.Calculate(Jurisdiction{Country: "IN", Region: "KA"}, Jurisdiction{Country: "IN"}, 10000)
+	assert.Equal(t, errors.Is(err, ErrUnknownRegion), true)
+}
+
+func TestEngine_Register(t *testing.T) {
+	e := NewEngine()
+	e.Register("br", RuleFunc(func(vendor, customer Jurisdiction, base int64) (Result, error) {
+		return newResult(base, []Line{{Name: "ISS", Rate: 5000}}), nil
+	}))
+
+	result, err := e.Calculate(Jurisdiction{Country: "BR"}, Jurisdiction{Country: "BR"}, 10000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, result.Lines[0].Amount, int64(500))
+}
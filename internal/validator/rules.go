@@ -0,0 +1,38 @@
+package validator
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// NotBlank reports whether value has anything but white space.
+func NotBlank(value string) bool {
+	return strings.TrimSpace(value) != ""
+}
+
+// MaxChars reports whether value is at most n characters (not bytes) long.
+func MaxChars(value string, n int) bool {
+	return utf8.RuneCountInString(value) <= n
+}
+
+// MinChars reports whether value is at least n characters long.
+func MinChars(value string, n int) bool {
+	return utf8.RuneCountInString(value) >= n
+}
+
+// Between reports whether value is within min and max, both included.
+func Between[T cmp.Ordered](value, min, max T) bool {
+	return value >= min && value <= max
+}
+
+// OneOf reports whether value is one of options.
+func OneOf[T comparable](value T, options ...T) bool {
+	return slices.Contains(options, value)
+}
+
+// Email reports whether value looks like an email address.
+func Email(value string) bool {
+	return EmailRX.MatchString(value)
+}
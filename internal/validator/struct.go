@@ -0,0 +1,241 @@
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Struct validates s, a struct or a pointer to one, from the validate tags
+// of its fields, for instance
+//
+//	Name  string        `validate:"required,max=200"`
+//	Email string        `validate:"email"`
+//	Items []InvoiceItem `validate:"required,dive"`
+//
+// Rules are applied in order and stop at the first one that fails. Apart
+// from required and notblank they skip zero values, so optional fields are
+// only checked when set. Rules written after dive apply to every element of
+// a slice, and struct elements are validated from their own tags; nested
+// struct fields always are.
+//
+// Errors are reported under the json name of a field when it has one, its
+// Go name otherwise. An unknown rule panics, as it's a programming error.
+//
+// The rules are:
+//
+//	required       not the zero value, or not empty for slices and maps
+//	notblank       not only white space
+//	min=n, max=n   at least / at most n: characters for strings, elements
+//	               for slices, the value itself for numbers
+//	len=n          exactly n characters or elements
+//	oneof=a b c    one of the space separated values
+//	email, date
+//
+// along with the rules registered by other packages with RegisterRule, such
+// as iban from banking or currency from money.
+func (v *Validator) Struct(s any) {
+	rv := reflect.ValueOf(s)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("validator: Struct called with %s", rv.Type()))
+	}
+
+	v.validateStruct(rv)
+}
+
+func (v *Validator) validateStruct(rv reflect.Value) {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+
+		v.Field(name).validateValue(rv.Field(i), splitRules(field.Tag.Get("validate")))
+	}
+}
+
+func fieldName(field reflect.StructField) string {
+	if tag := field.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func splitRules(tag string) []string {
+	if tag == "" {
+		return nil
+	}
+	return strings.Split(tag, ",")
+}
+
+// validateValue applies rules to value, the field v validates.
+func (v *Validator) validateValue(value reflect.Value, rules []string) {
+	for i, rule := range rules {
+		if rule == "dive" {
+			if value.Kind() != reflect.Slice && value.Kind() != reflect.Array {
+				panic(fmt.Sprintf("validator: dive on %s", value.Type()))
+			}
+			for j := 0; j < value.Len(); j++ {
+				v.Index(j).validateValue(value.Index(j), rules[i+1:])
+			}
+			return
+		}
+
+		name, param, _ := strings.Cut(rule, "=")
+		code, message, ok := applyRule(name, param, value)
+		if !ok {
+			v.AddErrorCode("", code, message)
+			return
+		}
+	}
+
+	for value.Kind() == reflect.Pointer && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Struct {
+		v.validateStruct(value)
+	}
+}
+
+// applyRule checks one rule against value, returning the error code and
+// message to report when it fails.
+func applyRule(name, param string, value reflect.Value) (code, message string, ok bool) {
+	switch name {
+	case "required":
+		return CodeRequired, "must be provided", !isEmpty(value)
+	case "notblank":
+		return CodeRequired, "must not be blank", value.Kind() == reflect.String && NotBlank(value.String())
+	}
+
+	if isEmpty(value) {
+		return "", "", true
+	}
+
+	switch name {
+	case "min", "max", "len":
+		return applyBound(name, param, value)
+	case "oneof":
+		options := strings.Fields(param)
+		return CodeNotAllowed, fmt.Sprintf("must be one of %v", options), OneOf(fmt.Sprint(value.Interface()), options...)
+	}
+
+	rule, ok := rules[name]
+	if !ok {
+		panic(fmt.Sprintf("validator: unknown rule %q", name))
+	}
+
+	return rule.Code, rule.Message, value.Kind() == reflect.String && rule.Check(value.String())
+}
+
+// Rule is a validate tag rule for string fields: Check tells if a value is
+// valid, Code and Message are the error reported when it isn't.
+type Rule struct {
+	Check   func(string) bool
+	Code    string
+	Message string
+}
+
+var rules = map[string]Rule{
+	"email": {Email, CodeInvalid, "must be a valid email address"},
+	"date":  {ValidateDate, CodeInvalid, "must be a date in the format YYYY-MM-DD"},
+}
+
+// RegisterRule makes rule usable in validate tags as name, so packages can
+// check their own identifiers without this one depending on them. It is
+// meant to be called from init functions and panics when name is taken.
+func RegisterRule(name string, rule Rule) {
+	_, taken := rules[name]
+	switch name {
+	case "required", "notblank", "min", "max", "len", "oneof", "dive":
+		taken = true
+	}
+	if taken {
+		panic(fmt.Sprintf("validator: rule %q is already taken", name))
+	}
+	rules[name] = rule
+}
+
+func applyBound(name, param string, value reflect.Value) (code, message string, ok bool) {
+	switch value.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n, err := strconv.Atoi(param)
+		if err != nil {
+			panic(fmt.Sprintf("validator: invalid %s=%s", name, param))
+		}
+
+		unit := "characters"
+		length := len([]rune(value.String()))
+		if value.Kind() != reflect.String {
+			unit, length = "items", value.Len()
+		}
+
+		switch name {
+		case "min":
+			return CodeOutOfRange, fmt.Sprintf("must have at least %d %s", n, unit), length >= n
+		case "max":
+			return CodeOutOfRange, fmt.Sprintf("must not have more than %d %s", n, unit), length <= n
+		default:
+			return CodeOutOfRange, fmt.Sprintf("must have exactly %d %s", n, unit), length == n
+		}
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validator: invalid %s=%s", name, param))
+		}
+		return boundMessage(name, param, value.Int(), n)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validator: invalid %s=%s", name, param))
+		}
+		return boundMessage(name, param, value.Uint(), n)
+
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			panic(fmt.Sprintf("validator: invalid %s=%s", name, param))
+		}
+		return boundMessage(name, param, value.Float(), n)
+	}
+
+	panic(fmt.Sprintf("validator: %s on %s", name, value.Type()))
+}
+
+func boundMessage[T int64 | uint64 | float64](name, param string, value, bound T) (code, message string, ok bool) {
+	switch name {
+	case "min":
+		return CodeOutOfRange, "must not be less than " + param, value >= bound
+	case "max":
+		return CodeOutOfRange, "must not be greater than " + param, value <= bound
+	default:
+		return CodeOutOfRange, "must be " + param, value == bound
+	}
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Slice, reflect.Map:
+		return value.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return value.IsNil()
+	}
+	return value.IsZero()
+}
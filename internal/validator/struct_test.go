@@ -0,0 +1,126 @@
+package validator
+
+import (
+	"strings"
+	"testing"
+
+	"tools.lucasfaria.dev/internal/assert"
+)
+
+func init() {
+	RegisterRule("digits", Rule{func(s string) bool { return strings.Trim(s, "0123456789") == "" }, CodeInvalid, "must only contain digits"})
+	RegisterRule("upper", Rule{func(s string) bool { return s == strings.ToUpper(s) }, CodeNotAllowed, "must be upper case"})
+}
+
+type testAccount struct {
+	Number string `json:"number" validate:"digits"`
+	Branch string `validate:"digits"`
+}
+
+type testOrder struct {
+	Reference string        `json:"reference" validate:"required,notblank,max=8"`
+	Email     string        `validate:"email"`
+	Currency  string        `validate:"upper"`
+	Placed    string        `validate:"date"`
+	Status    string        `validate:"oneof=open paid"`
+	Quantity  int           `validate:"min=1,max=10"`
+	Tags      []string      `validate:"max=2,dive,notblank"`
+	Accounts  []testAccount `validate:"required,dive"`
+	Primary   testAccount
+	internal  string `validate:"required"`
+}
+
+func TestValidator_Struct(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  testOrder
+		errors map[string]string
+	}{
+		{"Valid", testOrder{
+			Reference: "PO-1",
+			Email:     "mary@acme.com",
+			Currency:  "EUR",
+			Placed:    "2024-03-05",
+			Status:    "paid",
+			Quantity:  3,
+			Tags:      []string{"rush"},
+			Accounts:  []testAccount{{Number: "0532013000", Branch: "026001591"}},
+		}, map[string]string{}},
+		{"Required", testOrder{}, map[string]string{
+			"reference": CodeRequired,
+			"Accounts":  CodeRequired,
+		}},
+		{"Invalid values", testOrder{
+			Reference: "PO-123456789",
+			Email:     "mary",
+			Currency:  "eur",
+			Placed:    "2024-02-30",
+			Status:    "void",
+			Quantity:  11,
+			Tags:      []string{"a", " ", "c"},
+			Accounts:  []testAccount{{}, {Number: "DE88 3704", Branch: "02600159x"}},
+			Primary:   testAccount{Branch: "x"},
+		}, map[string]string{
+			"reference":          CodeOutOfRange,
+			"Email":              CodeInvalid,
+			"Currency":           CodeNotAllowed,
+			"Placed":             CodeInvalid,
+			"Status":             CodeNotAllowed,
+			"Quantity":           CodeOutOfRange,
+			"Tags":               CodeOutOfRange,
+			"Accounts[1].number": CodeInvalid,
+			"Accounts[1].Branch": CodeInvalid,
+			"Primary.Branch":     CodeInvalid,
+		}},
+		{"Dive", testOrder{
+			Reference: "PO-1",
+			Tags:      []string{" "},
+			Accounts:  []testAccount{{}},
+		}, map[string]string{
+			"Tags[0]": CodeRequired,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			v.Struct(&tt.order)
+
+			assert.Equal(t, len(v.Errors), len(tt.errors))
+			for key, code := range tt.errors {
+				if len(v.Errors[key]) != 1 {
+					t.Fatalf("got %v for %s; want one error", v.Errors[key], key)
+				}
+				assert.Equal(t, v.Errors[key][0].Code, code)
+			}
+		})
+	}
+}
+
+func TestValidator_StructNested(t *testing.T) {
+	v := New()
+	v.Field("orders").Index(2).Struct(testOrder{Reference: "PO-1", Accounts: []testAccount{{Number: "x"}}})
+
+	assert.Equal(t, len(v.Errors), 1)
+	assert.Equal(t, len(v.Errors["orders[2].Accounts[0].number"]), 1)
+}
+
+func TestRules(t *testing.T) {
+	assert.Equal(t, NotBlank(" \t"), false)
+	assert.Equal(t, MaxChars("Zürich", 6), true)
+	assert.Equal(t, MinChars("Zürich", 7), false)
+	assert.Equal(t, Between(5, 1, 5), true)
+	assert.Equal(t, Between(0.5, 1, 5), false)
+	assert.Equal(t, OneOf("b", "a", "b"), true)
+}
+
+func TestRegisterRule(t *testing.T) {
+	for _, name := range []string{"digits", "email", "max"} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				assert.Equal(t, recover() != nil, true)
+			}()
+			RegisterRule(name, Rule{Check: NotBlank})
+		})
+	}
+}
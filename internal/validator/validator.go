@@ -3,6 +3,8 @@ package validator
 import (
 	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -10,21 +12,78 @@ var (
 	EmailRX = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 )
 
+// Error codes are the machine-readable part of a FieldError; clients can
+// switch on them while the messages stay free to change.
+const (
+	CodeRequired   = "required"
+	CodeInvalid    = "invalid"
+	CodeOutOfRange = "out_of_range"
+	CodeNotAllowed = "not_allowed"
+	CodeMismatch   = "mismatch"
+)
+
+// FieldError is one problem with one field.
+type FieldError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Validator collects every error found, keyed by field path. Paths name
+// nested fields in dotted form with list indexes, for instance
+// "Items[3].UnitPrice"; see Field and Index.
 type Validator struct {
-	Errors map[string]string
+	Errors map[string][]FieldError
+	path   string
 }
 
 func New() *Validator {
-	return &Validator{Errors: make(map[string]string)}
+	return &Validator{Errors: make(map[string][]FieldError)}
+}
+
+// Field returns a validator for the field key below v. It shares its errors
+// with v, so checks made on it are reported under the full path.
+func (v *Validator) Field(key string) *Validator {
+	return &Validator{Errors: v.Errors, path: v.key(key)}
+}
+
+// Index returns a validator for the i-th element of the list v validates.
+func (v *Validator) Index(i int) *Validator {
+	return &Validator{Errors: v.Errors, path: v.path + "[" + strconv.Itoa(i) + "]"}
+}
+
+// key joins key to the validator path. An empty key is the path itself.
+func (v *Validator) key(key string) string {
+	switch {
+	case v.path == "":
+		return key
+	case key == "":
+		return v.path
+	case strings.HasPrefix(key, "["):
+		return v.path + key
+	default:
+		return v.path + "." + key
+	}
 }
 
+// Valid reports whether no errors were found, anywhere in the tree v shares
+// its errors with.
 func (v *Validator) Valid() bool {
 	return len(v.Errors) == 0
 }
 
+// AddError records message under key with the generic "invalid" code.
 func (v *Validator) AddError(key, message string) {
-	if _, exists := v.Errors[key]; !exists {
-		v.Errors[key] = message
+	v.AddErrorCode(key, CodeInvalid, message)
+}
+
+// AddErrorCode records message under key with code. A field can have any
+// number of errors; the same error is only recorded once.
+func (v *Validator) AddErrorCode(key, code, message string) {
+	key = v.key(key)
+	err := FieldError{Code: code, Message: message}
+
+	if !slices.Contains(v.Errors[key], err) {
+		v.Errors[key] = append(v.Errors[key], err)
 	}
 }
 
@@ -34,6 +93,26 @@ func (v *Validator) Check(ok bool, key, message string) {
 	}
 }
 
+func (v *Validator) CheckCode(ok bool, key, code, message string) {
+	if !ok {
+		v.AddErrorCode(key, code, message)
+	}
+}
+
+// Messages flattens the errors to one string per field, joining multiple
+// messages with "; ".
+func (v *Validator) Messages() map[string]string {
+	messages := make(map[string]string, len(v.Errors))
+	for key, errs := range v.Errors {
+		list := make([]string, len(errs))
+		for i, err := range errs {
+			list[i] = err.Message
+		}
+		messages[key] = strings.Join(list, "; ")
+	}
+	return messages
+}
+
 func PermittedValue[T comparable](value T, permittedValues ...T) bool {
 	return slices.Contains(permittedValues, value)
 }
@@ -36,7 +36,7 @@ func TestValidator_AddError(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			v.AddError(tt.key, tt.message)
-			assert.Equal(t, v.Errors[tt.key], tt.message)
+			assert.Equal(t, v.Errors[tt.key][0].Message, tt.message)
 		})
 	}
 }
@@ -163,3 +163,40 @@ func TestValidateDate(t *testing.T) {
 		})
 	}
 }
+
+func TestValidator_MultipleErrors(t *testing.T) {
+	v := New()
+	v.AddErrorCode("key", CodeRequired, "must be provided")
+	v.AddError("key", "must be valid")
+	v.AddError("key", "must be valid")
+
+	assert.Equal(t, len(v.Errors["key"]), 2)
+	assert.Equal(t, v.Errors["key"][0], FieldError{Code: CodeRequired, Message: "must be provided"})
+	assert.Equal(t, v.Errors["key"][1], FieldError{Code: CodeInvalid, Message: "must be valid"})
+	assert.Equal(t, v.Messages()["key"], "must be provided; must be valid")
+}
+
+func TestValidator_Field(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(v *Validator)
+		expected string
+	}{
+		{"Field", func(v *Validator) { v.Field("vendor").AddError("name", "") }, "vendor.name"},
+		{"Index", func(v *Validator) { v.Field("items").Index(3).AddError("price", "") }, "items[3].price"},
+		{"Nested", func(v *Validator) {
+			v.Field("paymentMethods").Index(1).Field("details").Index(0).AddError("value", "")
+		}, "paymentMethods[1].details[0].value"},
+		{"Empty key", func(v *Validator) { v.Field("items").AddError("", "") }, "items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			tt.validate(v)
+
+			assert.Equal(t, len(v.Errors[tt.expected]), 1)
+			assert.Equal(t, v.Valid(), false)
+		})
+	}
+}